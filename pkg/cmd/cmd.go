@@ -8,9 +8,11 @@ import (
 
 	"github.com/bingo-project/bingoctl/pkg/cmd/create"
 	"github.com/bingo-project/bingoctl/pkg/cmd/db"
+	"github.com/bingo-project/bingoctl/pkg/cmd/destroy"
 	"github.com/bingo-project/bingoctl/pkg/cmd/gen"
 	makecmd "github.com/bingo-project/bingoctl/pkg/cmd/make"
 	"github.com/bingo-project/bingoctl/pkg/cmd/migrate"
+	"github.com/bingo-project/bingoctl/pkg/cmd/status"
 	"github.com/bingo-project/bingoctl/pkg/cmd/version"
 	"github.com/bingo-project/bingoctl/pkg/config"
 )
@@ -41,6 +43,8 @@ used to quickly create and develop applications based on the Bingo framework.`,
 	// Add commands
 	cmds.AddCommand(version.NewCmdVersion())
 	cmds.AddCommand(makecmd.NewCmdMake())
+	cmds.AddCommand(destroy.NewCmdDestroy())
+	cmds.AddCommand(status.NewCmdStatus())
 	cmds.AddCommand(create.NewCmdCreate())
 	cmds.AddCommand(gen.NewCmdGen())
 	cmds.AddCommand(migrate.NewCmdMigrateWithRunner())
@@ -1,9 +1,11 @@
 // ABOUTME: Database management commands for bingoctl
-// ABOUTME: Parent command that groups database-related subcommands like seed
+// ABOUTME: Parent command that groups database-related subcommands like seed, export and import
 package db
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
 )
 
 var opt = NewOptions()
@@ -29,8 +31,11 @@ func NewCmdDB() *cobra.Command {
 
 	cmd.PersistentFlags().BoolVarP(&opt.Verbose, "verbose", "v", false, "Show detailed compilation output")
 	cmd.PersistentFlags().BoolVar(&opt.Rebuild, "rebuild", false, "Force rebuild binary")
+	config.AddConnectionFlag(cmd)
 
 	cmd.AddCommand(NewCmdSeed())
+	cmd.AddCommand(NewCmdExport())
+	cmd.AddCommand(NewCmdImport())
 
 	return cmd
 }
@@ -0,0 +1,143 @@
+// ABOUTME: Export command writing the rows of database tables to SQL, JSON or CSV files
+// ABOUTME: Streams the rows of the configured connection in batches, filtered by --where and --limit
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	cmdutil "github.com/bingo-project/component-base/cli/util"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	"github.com/bingo-project/bingoctl/pkg/dataset"
+	"github.com/bingo-project/bingoctl/pkg/db"
+)
+
+// ExportOptions is an option struct to support 'export' sub command.
+type ExportOptions struct {
+	*Options
+	Tables    []string
+	Format    string
+	Where     string
+	Limit     int
+	BatchSize int
+
+	// Output is the directory of the exported <table>.<format> files, - for stdout.
+	Output string
+}
+
+// NewExportOptions returns an initialized ExportOptions instance.
+func NewExportOptions() *ExportOptions {
+	return &ExportOptions{
+		Options:   opt,
+		Format:    dataset.FormatSQL,
+		BatchSize: dataset.DefaultBatchSize,
+		Output:    ".",
+	}
+}
+
+// NewCmdExport returns new initialized instance of 'export' sub command.
+func NewCmdExport() *cobra.Command {
+	o := NewExportOptions()
+
+	cmd := &cobra.Command{
+		Use:                   "export",
+		DisableFlagsInUseLine: true,
+		Short:                 "Export the rows of database tables to SQL, JSON or CSV files",
+		Example: `  bingo db export --tables users,posts
+  bingo db export -t users --format csv --where "status = 1" --limit 100 -o database/export
+  bingo db export -t users --format json -o -`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Complete())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&o.Tables, "tables", "t", nil, "Tables to export, separated by ','.")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", o.Format, "Output format: sql, json or csv.")
+	cmd.Flags().StringVar(&o.Where, "where", "", "SQL condition of the exported rows, e.g. \"status = 1\".")
+	cmd.Flags().IntVar(&o.Limit, "limit", 0, "Maximum number of rows exported per table, 0 for all.")
+	cmd.Flags().IntVar(&o.BatchSize, "batch-size", o.BatchSize, "Number of rows written at a time.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Directory of the <table>.<format> files, - for stdout.")
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *ExportOptions) Validate() error {
+	if len(o.Tables) == 0 {
+		return errors.New("no tables to export, use --tables")
+	}
+
+	if err := dataset.CheckFormat(o.Format); err != nil {
+		return err
+	}
+
+	if o.Output == "-" && len(o.Tables) > 1 && o.Format != dataset.FormatSQL {
+		return fmt.Errorf("can't write several tables to stdout as %s, use --output", o.Format)
+	}
+
+	return nil
+}
+
+// Complete completes all the required options.
+func (o *ExportOptions) Complete() error {
+	var err error
+	config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
+
+	return err
+}
+
+// Run executes the export command.
+func (o *ExportOptions) Run() error {
+	// Keep stdout for the rows when writing them there.
+	log := io.Writer(os.Stdout)
+	if o.Output == "-" {
+		log = os.Stderr
+	} else if err := os.MkdirAll(o.Output, 0755); err != nil {
+		return err
+	}
+
+	for _, table := range o.Tables {
+		file := filepath.Join(o.Output, table+"."+o.Format)
+		fmt.Fprintf(log, "%s %s\n", ansi.Color("Exporting:", "yellow"), table)
+
+		count, err := o.export(table, file)
+		if err != nil {
+			return fmt.Errorf("exporting %s: %w", table, err)
+		}
+
+		if o.Output == "-" {
+			file = "stdout"
+		}
+		fmt.Fprintf(log, "%s  %s (%d rows) to %s\n", ansi.Color("Exported:", "green"), table, count, file)
+	}
+
+	return nil
+}
+
+// export writes the rows of table to file, or to stdout.
+func (o *ExportOptions) export(table, file string) (int, error) {
+	opts := dataset.ExportOptions{Format: o.Format, Where: o.Where, Limit: o.Limit, BatchSize: o.BatchSize}
+	if o.Output == "-" {
+		return dataset.Export(config.DB, os.Stdout, table, opts)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := dataset.Export(config.DB, f, table, opts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return count, err
+}
@@ -0,0 +1,223 @@
+// ABOUTME: Import command loading SQL, JSON or CSV files written by db export into the database
+// ABOUTME: Inserts the rows of each file in batches within a transaction, parents before the tables referencing them
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	cmdutil "github.com/bingo-project/component-base/cli/util"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	"github.com/bingo-project/bingoctl/pkg/dataset"
+	"github.com/bingo-project/bingoctl/pkg/db"
+)
+
+// ImportOptions is an option struct to support 'import' sub command.
+type ImportOptions struct {
+	*Options
+	Files []string
+
+	// Table overrides the table named after the file, e.g. users for users.json.
+	Table string
+
+	// Format overrides the format of the file extension, required to read stdin.
+	Format    string
+	BatchSize int
+}
+
+// NewImportOptions returns an initialized ImportOptions instance.
+func NewImportOptions() *ImportOptions {
+	return &ImportOptions{
+		Options:   opt,
+		BatchSize: dataset.DefaultBatchSize,
+	}
+}
+
+// NewCmdImport returns new initialized instance of 'import' sub command.
+func NewCmdImport() *cobra.Command {
+	o := NewImportOptions()
+
+	cmd := &cobra.Command{
+		Use:                   "import FILE|DIR...",
+		DisableFlagsInUseLine: true,
+		Short:                 "Import the rows of SQL, JSON or CSV files written by db export",
+		Example: `  bingo db import users.sql posts.sql
+  bingo db import database/export
+  bingo db import accounts.csv --table users
+  cat users.json | bingo db import - --format json --table users`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Table, "table", "", "Table to import a JSON or CSV file into, default the file name.")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "Input format: sql, json or csv, default the file extension.")
+	cmd.Flags().IntVar(&o.BatchSize, "batch-size", o.BatchSize, "Number of rows inserted at a time.")
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *ImportOptions) Validate(args []string) error {
+	for _, arg := range args {
+		files, err := importFiles(arg)
+		if err != nil {
+			return err
+		}
+		o.Files = append(o.Files, files...)
+	}
+
+	if o.Table != "" && len(o.Files) > 1 {
+		return errors.New("--table can't be used with several files")
+	}
+
+	for _, file := range o.Files {
+		format := o.format(file)
+		if file == "-" && format == "" {
+			return errors.New("--format is required to read stdin")
+		}
+		if err := dataset.CheckFormat(format); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if file == "-" && format != dataset.FormatSQL && o.Table == "" {
+			return errors.New("--table is required to read rows from stdin")
+		}
+		if format == dataset.FormatSQL && o.Table != "" {
+			return fmt.Errorf("%s: --table can't be used with SQL files, their statements name the tables", file)
+		}
+	}
+
+	return nil
+}
+
+// Complete completes all the required options.
+func (o *ImportOptions) Complete() error {
+	var err error
+	config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
+	if err != nil {
+		return err
+	}
+
+	return o.sortFiles()
+}
+
+// Run executes the import command.
+func (o *ImportOptions) Run() error {
+	for _, file := range o.Files {
+		fmt.Printf("%s %s\n", ansi.Color("Importing:", "yellow"), file)
+
+		count, err := o.importFile(file)
+		if err != nil {
+			return fmt.Errorf("importing %s: %w", file, err)
+		}
+
+		fmt.Printf("%s  %s (%d rows)\n", ansi.Color("Imported:", "green"), file, count)
+	}
+
+	return nil
+}
+
+// sortFiles orders the files so that the rows of a table are imported after the rows of the tables it
+// references with foreign keys, files of unrelated tables keep their order.
+func (o *ImportOptions) sortFiles() error {
+	var tables []string
+	for _, file := range o.Files {
+		if table := o.table(file); !slices.Contains(tables, table) {
+			tables = append(tables, table)
+		}
+	}
+
+	tables, err := dataset.SortTables(config.DB, tables)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(o.Files, func(i, j int) bool {
+		return slices.Index(tables, o.table(o.Files[i])) < slices.Index(tables, o.table(o.Files[j]))
+	})
+
+	return nil
+}
+
+// importFile imports the rows of file, or of stdin for -.
+func (o *ImportOptions) importFile(file string) (int, error) {
+	table := o.table(file)
+	opts := dataset.ImportOptions{Format: o.format(file), BatchSize: o.BatchSize}
+
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	return dataset.Import(config.DB, r, table, opts)
+}
+
+// table returns the table of file, --table or the file name without extension.
+func (o *ImportOptions) table(file string) string {
+	if o.Table != "" {
+		return o.Table
+	}
+
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+// format returns the format of file, --format or its extension.
+func (o *ImportOptions) format(file string) string {
+	if o.Format != "" || file == "-" {
+		return o.Format
+	}
+
+	return dataset.FormatOf(file)
+}
+
+// importFiles returns path, or the SQL, JSON and CSV files of the directory path sorted by name. Complete
+// orders them by foreign keys.
+func importFiles(path string) ([]string, error) {
+	if path == "-" {
+		return []string{path}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || dataset.CheckFormat(dataset.FormatOf(entry.Name())) != nil {
+			continue
+		}
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no SQL, JSON or CSV files in %s", path)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
@@ -13,12 +13,25 @@ import (
 type SeedOptions struct {
 	*Options
 	Seeder string
+
+	// Force reruns registered seeders which already ran.
+	Force bool
+
+	// Status lists the registered seeders instead of running them.
+	Status bool
+	Output string
+
+	// Fixtures is the directory of the fixture files to load instead of running seeders.
+	Fixtures string
+	Upsert   bool
+	Truncate bool
 }
 
 // NewSeedOptions returns an initialized SeedOptions instance.
 func NewSeedOptions() *SeedOptions {
 	return &SeedOptions{
 		Options: opt,
+		Output:  "table",
 	}
 }
 
@@ -36,6 +49,12 @@ func NewCmdSeed() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&o.Seeder, "seeder", "", "The class name of the seeder to run")
+	cmd.Flags().BoolVar(&o.Force, "force", false, "Rerun registered seeders which already ran.")
+	cmd.Flags().BoolVar(&o.Status, "status", false, "List the registered seeders and when they ran.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Status output format: table or json.")
+	cmd.Flags().StringVar(&o.Fixtures, "fixtures", "", "Load the YAML, JSON and CSV fixture files in this directory instead of running seeders.")
+	cmd.Flags().BoolVar(&o.Upsert, "upsert", false, "Update fixture rows whose primary key exists.")
+	cmd.Flags().BoolVar(&o.Truncate, "truncate", false, "Delete the rows of the fixture tables before loading.")
 
 	return cmd
 }
@@ -46,5 +65,13 @@ func (o *SeedOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	return r.Run(o.Seeder)
+
+	if o.Status {
+		return r.Status(o.Output)
+	}
+	if o.Fixtures != "" {
+		return r.Fixtures(o.Fixtures, o.Upsert, o.Truncate)
+	}
+
+	return r.Run(o.Seeder, o.Force)
 }
@@ -0,0 +1,168 @@
+package destroy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bingo-project/component-base/cli/console"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/generator"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+var (
+	destroyExample = `# Delete the crud code of post and remove it from the store, biz and router registries
+bingo destroy crud post
+
+# Preview what would be deleted
+bingo destroy crud post --dry-run`
+
+	opt = &generator.Options{}
+)
+
+// NewCmdDestroy returns new initialized instance of 'destroy' sub command.
+func NewCmdDestroy() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "destroy COMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 "Delete generated code and its registry entries",
+		Example:               destroyExample,
+		Run:                   cmdutil.DefaultSubCommandRun(),
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if !cmdutil.DryRun {
+				return nil
+			}
+
+			return cmdutil.PrintChanges(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&opt.Directory, "directory", "d", "", "Where the file was created.")
+	cmd.PersistentFlags().StringVarP(&opt.PackageName, "package", "p", "", "Name of the package.")
+	cmd.PersistentFlags().StringVarP(&opt.Service, "service", "s", "", "Target service name for path inference")
+	cmd.PersistentFlags().BoolVar(&cmdutil.DryRun, "dry-run", false, "Preview the files and registry edits without writing to disk.")
+	cmd.PersistentFlags().BoolVarP(&cmdutil.Overwrite, "force", "f", false, "Delete without confirmation, edited files included.")
+
+	// Add subcommands
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplCmd, "Delete command line code"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplModel, "Delete model code"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplStore, "Delete store code and unregister it"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplRequest, "Delete request code"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplBiz, "Delete biz code and unregister it"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplHandler, "Delete handler code and its routes"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplMiddleware, "Delete middleware code"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplJob, "Delete job code"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplMigration, "Delete migration files"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplSeeder, "Delete seeder code"))
+	cmd.AddCommand(NewCmdDestroyCode(generator.TmplFactory, "Delete factory code"))
+	cmd.AddCommand(NewCmdDestroyCrud())
+
+	return cmd
+}
+
+// DestroyOptions is an option struct to support 'destroy' sub commands.
+type DestroyOptions struct {
+	*generator.Options
+
+	// Tmpls are destroyed in order.
+	Tmpls []generator.Tmpl
+}
+
+// NewDestroyOptions returns an initialized DestroyOptions instance.
+func NewDestroyOptions(tmpls ...generator.Tmpl) *DestroyOptions {
+	return &DestroyOptions{
+		Options: opt,
+		Tmpls:   tmpls,
+	}
+}
+
+// NewCmdDestroyCode returns new initialized instance of a 'destroy' sub command for tmpl.
+func NewCmdDestroyCode(tmpl generator.Tmpl, short string) *cobra.Command {
+	return newCmdDestroy(string(tmpl), short, NewDestroyOptions(tmpl))
+}
+
+// NewCmdDestroyCrud returns new initialized instance of 'destroy crud' sub command.
+func NewCmdDestroyCrud() *cobra.Command {
+	o := NewDestroyOptions(
+		generator.TmplHandler,
+		generator.TmplBiz,
+		generator.TmplRequest,
+		generator.TmplStore,
+		generator.TmplModel,
+	)
+
+	return newCmdDestroy("crud", "Delete crud code and unregister it", o)
+}
+
+func newCmdDestroy(name, short string, o *DestroyOptions) *cobra.Command {
+	usageStr := name + " NAME"
+
+	return &cobra.Command{
+		Use:                   usageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 short,
+		TraverseChildren:      true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(cmd, args, usageStr))
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *DestroyOptions) Validate(cmd *cobra.Command, args []string, usageStr string) error {
+	if len(args) < 1 {
+		return cmdutil.UsageErrorf(cmd, "expected '%s'.\nNAME is a required argument for the %s command", usageStr, cmd.Name())
+	}
+
+	return nil
+}
+
+// Complete completes all the required options.
+func (o *DestroyOptions) Complete(cmd *cobra.Command, args []string) error {
+	if cmdutil.Overwrite || cmdutil.DryRun {
+		return nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Delete the %s code of %s", cmd.Name(), args[0]),
+		IsConfirm: true,
+	}
+
+	_, err := prompt.Run()
+	if err != nil {
+		console.Exit("skipped.")
+	}
+
+	return nil
+}
+
+// Run executes a new sub command using the specified options.
+func (o *DestroyOptions) Run(args []string) error {
+	if len(o.Tmpls) == 1 {
+		return o.DestroyCode(string(o.Tmpls[0]), args[0])
+	}
+
+	// Destroy the other layers when one fails, and report all the failures.
+	var errs []error
+	for i, tmpl := range o.Tmpls {
+		if i > 0 {
+			o.ReSetDirectory()
+		}
+
+		err := o.DestroyCode(string(tmpl), args[0])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tmpl, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	fmt.Println("done.")
+
+	return nil
+}
@@ -43,6 +43,7 @@ func NewCmdGen() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.TableStr, "tables", "t", "", "data tables, separated by ',', example:'user,post'.")
+	config.AddConnectionFlag(cmd)
 
 	return cmd
 }
@@ -58,7 +59,7 @@ func (o *GenOptions) Validate(cmd *cobra.Command, args []string) error {
 func (o *GenOptions) Complete(cmd *cobra.Command, args []string) error {
 	// Init store
 	var err error
-	config.DB, err = db.NewMySQL(config.Cfg.MysqlOptions)
+	config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
 
 	return err
 }
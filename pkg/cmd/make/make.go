@@ -3,6 +3,7 @@ package make
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/bingo-project/bingoctl/pkg/config"
 	"github.com/bingo-project/bingoctl/pkg/generator"
 	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
 )
@@ -25,12 +26,22 @@ func NewCmdMake() *cobra.Command {
 		Short:                 "Generate code",
 		Example:               makeExample,
 		Run:                   cmdutil.DefaultSubCommandRun(),
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if !cmdutil.DryRun {
+				return nil
+			}
+
+			return cmdutil.PrintChanges(cmd.OutOrStdout())
+		},
 	}
 
 	cmd.PersistentFlags().StringVarP(&opt.Directory, "directory", "d", "", "Where to create the file.")
 	cmd.PersistentFlags().StringVarP(&opt.PackageName, "package", "p", "", "Name of the package.")
 	cmd.PersistentFlags().StringVarP(&opt.Table, "table", "t", "", "Read fields from db table.")
+	cmd.PersistentFlags().StringVar(&opt.FieldSpec, "fields", "", "Field definitions without db, example:'name:string:unique,age:int:nullable'.")
 	cmd.PersistentFlags().StringVarP(&opt.Service, "service", "s", "", "Target service name for path inference")
+	cmd.PersistentFlags().BoolVar(&cmdutil.DryRun, "dry-run", false, "Preview the files and registry edits without writing to disk.")
+	config.AddConnectionFlag(cmd)
 
 	// Add subcommands
 	cmd.AddCommand(NewCmdCMD())
@@ -44,7 +55,9 @@ func NewCmdMake() *cobra.Command {
 	cmd.AddCommand(NewCmdJob())
 	cmd.AddCommand(NewCmdMigration())
 	cmd.AddCommand(NewCmdSeeder())
+	cmd.AddCommand(NewCmdFactory())
 	cmd.AddCommand(NewCmdService())
+	cmd.AddCommand(NewCmdPublishTemplates())
 
 	return cmd
 }
@@ -58,7 +58,7 @@ func NewCmdBiz() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *BizOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, bizUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", bizUsageErrStr)
 	}
 
 	return nil
@@ -68,8 +68,8 @@ func (o *BizOptions) Validate(cmd *cobra.Command, args []string) error {
 func (o *BizOptions) Complete(cmd *cobra.Command, args []string) error {
 	// Init store if generating model by tables.
 	var err error
-	if o.Table != "" {
-		config.DB, err = db.NewMySQL(config.Cfg.MysqlOptions)
+	if o.UseDB() {
+		config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
 	}
 
 	return err
@@ -54,7 +54,7 @@ func NewCmdCMD() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *CmdOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, cmdUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", cmdUsageErrStr)
 	}
 
 	if len(args) > 1 {
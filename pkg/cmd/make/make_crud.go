@@ -52,6 +52,7 @@ func NewCmdCrud() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.Table, "table", "t", "", "generate model by table, example:'post'.")
+	cmd.Flags().BoolVar(&o.WithTests, "with-tests", false, "Also generate tests for store, biz and handler.")
 
 	return cmd
 }
@@ -59,7 +60,7 @@ func NewCmdCrud() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *CrudOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, crudUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", crudUsageErrStr)
 	}
 
 	return nil
@@ -69,8 +70,8 @@ func (o *CrudOptions) Validate(cmd *cobra.Command, args []string) error {
 func (o *CrudOptions) Complete(cmd *cobra.Command, args []string) error {
 	// Init store if generating model by tables.
 	var err error
-	if o.Table != "" {
-		config.DB, err = db.NewMySQL(config.Cfg.MysqlOptions)
+	if o.UseDB() {
+		config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
 	}
 
 	return err
@@ -0,0 +1,81 @@
+package make
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	"github.com/bingo-project/bingoctl/pkg/db"
+	"github.com/bingo-project/bingoctl/pkg/generator"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+const (
+	factoryUsageStr = "factory NAME"
+)
+
+var (
+	factoryUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nNAME is a required argument for the factory command",
+		factoryUsageStr,
+	)
+)
+
+// FactoryOptions is an option struct to support 'factory' sub command.
+type FactoryOptions struct {
+	*generator.Options
+}
+
+// NewFactoryOptions returns an initialized FactoryOptions instance.
+func NewFactoryOptions() *FactoryOptions {
+	return &FactoryOptions{
+		Options: opt,
+	}
+}
+
+// NewCmdFactory returns new initialized instance of 'factory' sub command.
+func NewCmdFactory() *cobra.Command {
+	o := NewFactoryOptions()
+
+	cmd := &cobra.Command{
+		Use:                   factoryUsageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 "Generate model factory code with fake values, fields are read from the model struct by default",
+		TraverseChildren:      true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.ModelName, "model", "m", "", "Model name.")
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *FactoryOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return cmdutil.UsageErrorf(cmd, "%s", factoryUsageErrStr)
+	}
+
+	return nil
+}
+
+// Complete completes all the required options.
+func (o *FactoryOptions) Complete(cmd *cobra.Command, args []string) error {
+	// Init store if reading fields from table.
+	var err error
+	if o.UseDB() {
+		config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
+	}
+
+	return err
+}
+
+// Run executes a new sub command using the specified options.
+func (o *FactoryOptions) Run(args []string) error {
+	return o.GenerateCode(string(generator.TmplFactory), args[0])
+}
@@ -56,7 +56,7 @@ func NewCmdHandler() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *HandlerOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, handlerUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", handlerUsageErrStr)
 	}
 
 	return nil
@@ -54,7 +54,7 @@ func NewCmdJob() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *JobOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, jobUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", jobUsageErrStr)
 	}
 
 	return nil
@@ -54,7 +54,7 @@ func NewCmdMiddleware() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *MiddlewareOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, middlewareUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", middlewareUsageErrStr)
 	}
 
 	return nil
@@ -25,6 +25,8 @@ var (
 // MigrationOptions is an option struct to support 'migration' sub command.
 type MigrationOptions struct {
 	*generator.Options
+
+	Diff bool
 }
 
 // NewMigrationOptions returns an initialized MigrationOptions instance.
@@ -50,13 +52,15 @@ func NewCmdMigration() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&o.Diff, "diff", false, "Generate the columns and indexes of the models missing in the database.")
+
 	return cmd
 }
 
 // Validate makes sure there is no discrepancy in command options.
 func (o *MigrationOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, migrationUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", migrationUsageErrStr)
 	}
 
 	return nil
@@ -64,10 +68,10 @@ func (o *MigrationOptions) Validate(cmd *cobra.Command, args []string) error {
 
 // Complete completes all the required options.
 func (o *MigrationOptions) Complete(cmd *cobra.Command, args []string) error {
-	// Init store if generating model by tables.
+	// Init store if generating model by tables or diffing the models with the database.
 	var err error
-	if o.Table != "" {
-		config.DB, err = db.NewMySQL(config.Cfg.MysqlOptions)
+	if o.UseDB() || o.Diff {
+		config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
 	}
 
 	return err
@@ -75,5 +79,9 @@ func (o *MigrationOptions) Complete(cmd *cobra.Command, args []string) error {
 
 // Run executes a new sub command using the specified options.
 func (o *MigrationOptions) Run(args []string) error {
+	if o.Diff {
+		return o.GenerateMigrationDiff(args[0])
+	}
+
 	return o.GenerateCode(string(generator.TmplMigration), args[0])
 }
@@ -56,7 +56,7 @@ func NewCmdModel() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *ModelOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, modelUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", modelUsageErrStr)
 	}
 
 	return nil
@@ -66,8 +66,8 @@ func (o *ModelOptions) Validate(cmd *cobra.Command, args []string) error {
 func (o *ModelOptions) Complete(cmd *cobra.Command, args []string) error {
 	// Init store if generating model by tables.
 	var err error
-	if o.Table != "" {
-		config.DB, err = db.NewMySQL(config.Cfg.MysqlOptions)
+	if o.UseDB() {
+		config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
 	}
 
 	return err
@@ -0,0 +1,87 @@
+package make
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	"github.com/bingo-project/bingoctl/pkg/generator"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+const (
+	publishTemplatesUsageStr = "publish-templates [NAME...]"
+)
+
+// PublishTemplatesOptions is an option struct to support 'publish-templates' sub command.
+type PublishTemplatesOptions struct {
+	Force bool
+	User  bool
+
+	dir string
+}
+
+// NewPublishTemplatesOptions returns an initialized PublishTemplatesOptions instance.
+func NewPublishTemplatesOptions() *PublishTemplatesOptions {
+	return &PublishTemplatesOptions{}
+}
+
+// NewCmdPublishTemplates returns new initialized instance of 'publish-templates' sub command.
+func NewCmdPublishTemplates() *cobra.Command {
+	o := NewPublishTemplatesOptions()
+
+	cmd := &cobra.Command{
+		Use:                   publishTemplatesUsageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 "Copy the built-in templates to the project for customization",
+		Long: `Copy the built-in templates to the project template directory (template.directory in .bingo.yaml,
+default .bingo/templates), or to ~/.bingo/templates/make with --user.
+
+Templates are looked up in the project directory first, then the user directory, then the built-in defaults.
+Pass generator names (e.g. handler, biz, service) to publish only their templates.`,
+		Example: `bingo make publish-templates
+bingo make publish-templates handler biz
+bingo make publish-templates --user`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.Force, "force", "f", false, "Overwrite templates that already exist.")
+	cmd.Flags().BoolVar(&o.User, "user", false, "Publish to the user template directory ~/.bingo/templates/make.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *PublishTemplatesOptions) Complete(cmd *cobra.Command, args []string) error {
+	if o.User {
+		dir, err := generator.UserTemplateDir()
+		if err != nil {
+			return err
+		}
+		o.dir = dir
+
+		return nil
+	}
+
+	o.dir = config.Cfg.GetTemplateDirectory()
+
+	return nil
+}
+
+// Run executes a new sub command using the specified options.
+func (o *PublishTemplatesOptions) Run(args []string) error {
+	published, err := generator.PublishTemplates(o.dir, o.Force, args...)
+	if err != nil {
+		return err
+	}
+
+	if len(published) == 0 {
+		fmt.Println("Nothing to publish.")
+	}
+
+	return nil
+}
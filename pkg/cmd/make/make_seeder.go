@@ -54,7 +54,7 @@ func NewCmdSeeder() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *SeedOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, seederUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", seederUsageErrStr)
 	}
 
 	return nil
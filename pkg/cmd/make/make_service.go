@@ -69,7 +69,7 @@ func NewCmdService() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *ServiceOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, serviceUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", serviceUsageErrStr)
 	}
 
 	o.ServiceName = args[0]
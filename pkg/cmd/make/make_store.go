@@ -58,7 +58,7 @@ func NewCmdStore() *cobra.Command {
 // Validate makes sure there is no discrepancy in command options.
 func (o *StoreOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
-		return cmdutil.UsageErrorf(cmd, storeUsageErrStr)
+		return cmdutil.UsageErrorf(cmd, "%s", storeUsageErrStr)
 	}
 
 	return nil
@@ -68,8 +68,8 @@ func (o *StoreOptions) Validate(cmd *cobra.Command, args []string) error {
 func (o *StoreOptions) Complete(cmd *cobra.Command, args []string) error {
 	// Init store if generating model by tables.
 	var err error
-	if o.Table != "" {
-		config.DB, err = db.NewMySQL(config.Cfg.MysqlOptions)
+	if o.UseDB() {
+		config.DB, err = db.NewDB(config.Cfg.GetDatabaseOptions())
 	}
 
 	return err
@@ -2,13 +2,19 @@ package migrate
 
 import (
 	"errors"
+	"time"
 
+	"github.com/bingo-project/component-base/cli/console"
 	"github.com/spf13/cobra"
 	"gorm.io/gorm"
 
+	"github.com/bingo-project/bingoctl/pkg/config"
 	"github.com/bingo-project/bingoctl/pkg/migrate"
 )
 
+// defaultMigrationDir is the migration directory when .bingo.yaml doesn't configure one.
+const defaultMigrationDir = "internal/pkg/database/migration"
+
 var (
 	opt = NewOptions()
 
@@ -22,6 +28,13 @@ type Options struct {
 	Force      bool
 	Verbose    bool
 	Rebuild    bool
+	Pretend    bool
+
+	// AllowOutOfOrder runs pending migrations older than the last ran migration.
+	AllowOutOfOrder bool
+
+	// LockTimeout overrides migrate.lockTimeout of .bingo.yaml.
+	LockTimeout time.Duration
 }
 
 // NewOptions returns an initialized Options instance.
@@ -44,7 +57,10 @@ func NewCmdMigrateWithRunner() *cobra.Command {
 	opt.DB = nil
 	opt.Production = false
 
-	return newMigrateCmd()
+	cmd := newMigrateCmd()
+	config.AddConnectionFlag(cmd)
+
+	return cmd
 }
 
 func newMigrateCmd() *cobra.Command {
@@ -57,6 +73,7 @@ func newMigrateCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(&opt.Force, "force", "f", false, "Force run migration command in production")
 	cmd.PersistentFlags().BoolVarP(&opt.Verbose, "verbose", "v", false, "Show detailed compilation output")
 	cmd.PersistentFlags().BoolVar(&opt.Rebuild, "rebuild", false, "Force rebuild migration binary")
+	cmd.PersistentFlags().DurationVar(&opt.LockTimeout, "lock-timeout", 0, "How long to wait for another process running migrations (default 30s)")
 
 	// Add sub commands.
 	cmd.AddCommand(NewCmdUp())
@@ -64,6 +81,10 @@ func newMigrateCmd() *cobra.Command {
 	cmd.AddCommand(NewCmdRefresh())
 	cmd.AddCommand(NewCmdFresh())
 	cmd.AddCommand(NewCmdReset())
+	cmd.AddCommand(NewCmdStatus())
+	cmd.AddCommand(NewCmdGoto())
+	cmd.AddCommand(NewCmdDump())
+	cmd.AddCommand(NewCmdLint())
 
 	return cmd
 }
@@ -75,5 +96,55 @@ func (o *Options) UseRunner() bool {
 
 // Migrator returns a new Migrator instance for direct DB execution.
 func (o *Options) Migrator() *migrate.Migrator {
-	return migrate.NewMigrator(o.DB)
+	migrator := migrate.NewMigrator(o.DB)
+	migrator.Pretend = o.Pretend
+	migrator.LockTimeout = o.lockTimeout()
+	migrator.AllowOutOfOrder = o.AllowOutOfOrder
+
+	return migrator
+}
+
+// ConfirmProduction exits if running in production without --force, pretending is always allowed.
+func (o *Options) ConfirmProduction() {
+	if o.Production && !o.Force && !o.Pretend {
+		console.Exit(ErrInProduction.Error())
+	}
+}
+
+// RunnerArgs returns args followed by the flags of the options passed to the runner binary.
+func (o *Options) RunnerArgs(args ...string) []string {
+	if o.Pretend {
+		args = append(args, "--pretend")
+	}
+	if o.AllowOutOfOrder {
+		args = append(args, "--allow-out-of-order")
+	}
+	if timeout := o.lockTimeout(); timeout > 0 {
+		args = append(args, "--lock-timeout", timeout.String())
+	}
+
+	return args
+}
+
+// migrationDir returns the migration directory of .bingo.yaml.
+func (o *Options) migrationDir() string {
+	if config.Cfg == nil || config.Cfg.GetMigrationDirectory() == "" {
+		return defaultMigrationDir
+	}
+
+	return config.Cfg.GetMigrationDirectory()
+}
+
+// lockTimeout returns the --lock-timeout flag, falling back to the config file.
+func (o *Options) lockTimeout() time.Duration {
+	if o.LockTimeout > 0 || config.Cfg == nil {
+		return o.LockTimeout
+	}
+
+	return config.Cfg.Migrate.LockTimeout
+}
+
+// addPretendFlag adds the --pretend flag to cmd.
+func addPretendFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&opt.Pretend, "pretend", false, "Print the SQL of the migrations without running them.")
 }
@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	cmdutil "github.com/bingo-project/component-base/cli/util"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/migrate"
+	"github.com/bingo-project/bingoctl/pkg/migrate/runner"
+)
+
+const (
+	dumpUsageStr = "dump"
+)
+
+// DumpOptions is an option struct to support 'dump' sub command.
+type DumpOptions struct {
+	*Options
+
+	Prune bool
+}
+
+// NewDumpOptions returns an initialized DumpOptions instance.
+func NewDumpOptions() *DumpOptions {
+	return &DumpOptions{
+		Options: opt,
+	}
+}
+
+// NewCmdDump returns new initialized instance of 'dump' sub command.
+func NewCmdDump() *cobra.Command {
+	o := NewDumpOptions()
+
+	cmd := &cobra.Command{
+		Use:                   dumpUsageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 "Dump the database schema and the ran migrations to database/schema",
+		TraverseChildren:      true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Prune, "prune", false, "Delete the files of the migrations squashed into the dump.")
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *DumpOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes a new sub command using the specified options.
+func (o *DumpOptions) Run(args []string) error {
+	if o.UseRunner() {
+		r, err := runner.NewRunner(o.Verbose, o.Rebuild)
+		if err != nil {
+			return err
+		}
+
+		var runnerArgs []string
+		if o.Prune {
+			runnerArgs = append(runnerArgs, "--prune-dir", r.MigrationDir())
+		}
+
+		return r.Run("dump", runnerArgs...)
+	}
+
+	names, err := o.Migrator().Dump()
+	if err != nil || !o.Prune {
+		return err
+	}
+
+	return migrate.PruneMigrationFiles(o.migrationDir(), names)
+}
@@ -1,7 +1,6 @@
 package migrate
 
 import (
-	"github.com/bingo-project/component-base/cli/console"
 	cmdutil "github.com/bingo-project/component-base/cli/util"
 	"github.com/spf13/cobra"
 
@@ -39,14 +38,14 @@ func NewCmdFresh() *cobra.Command {
 		},
 	}
 
+	addPretendFlag(cmd)
+
 	return cmd
 }
 
 // Validate makes sure there is no discrepancy in command options.
 func (o *FreshOptions) Validate(cmd *cobra.Command, args []string) error {
-	if o.Production && !o.Force {
-		console.Exit(ErrInProduction.Error())
-	}
+	o.ConfirmProduction()
 
 	return nil
 }
@@ -58,10 +57,8 @@ func (o *FreshOptions) Run(args []string) error {
 		if err != nil {
 			return err
 		}
-		return r.Run("fresh")
+		return r.Run("fresh", o.RunnerArgs()...)
 	}
 
-	o.Migrator().Fresh()
-
-	return nil
+	return o.Migrator().Fresh()
 }
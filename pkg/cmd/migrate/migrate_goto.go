@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/bingo-project/component-base/cli/console"
+	cmdutil "github.com/bingo-project/component-base/cli/util"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/migrate/runner"
+)
+
+const (
+	gotoUsageStr = "goto NAME"
+)
+
+var gotoUsageErrStr = fmt.Sprintf(
+	"expected '%s'.\nNAME is the migration to go to, e.g. 2024_01_02_150405_create_posts_table",
+	gotoUsageStr,
+)
+
+// GotoOptions is an option struct to support 'goto' sub command.
+type GotoOptions struct {
+	*Options
+}
+
+// NewGotoOptions returns an initialized GotoOptions instance.
+func NewGotoOptions() *GotoOptions {
+	return &GotoOptions{
+		Options: opt,
+	}
+}
+
+// NewCmdGoto returns new initialized instance of 'goto' sub command.
+func NewCmdGoto() *cobra.Command {
+	o := NewGotoOptions()
+
+	cmd := &cobra.Command{
+		Use:                   gotoUsageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 "Run or roll back migrations until the given migration is the last one ran",
+		TraverseChildren:      true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *GotoOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, "%s", gotoUsageErrStr)
+	}
+
+	if o.Production && !o.Force {
+		console.Exit(ErrInProduction.Error())
+	}
+
+	return nil
+}
+
+// Run executes a new sub command using the specified options.
+func (o *GotoOptions) Run(args []string) error {
+	if o.UseRunner() {
+		r, err := runner.NewRunner(o.Verbose, o.Rebuild)
+		if err != nil {
+			return err
+		}
+		return r.Run("goto", o.RunnerArgs(args[0])...)
+	}
+
+	return o.Migrator().Goto(args[0])
+}
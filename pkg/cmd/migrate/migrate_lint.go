@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"os"
+
+	cmdutil "github.com/bingo-project/component-base/cli/util"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/migrate"
+	"github.com/bingo-project/bingoctl/pkg/migrate/runner"
+)
+
+const (
+	lintUsageStr = "lint"
+)
+
+// LintOptions is an option struct to support 'lint' sub command.
+type LintOptions struct {
+	*Options
+}
+
+// NewLintOptions returns an initialized LintOptions instance.
+func NewLintOptions() *LintOptions {
+	return &LintOptions{
+		Options: opt,
+	}
+}
+
+// NewCmdLint returns new initialized instance of 'lint' sub command.
+func NewCmdLint() *cobra.Command {
+	o := NewLintOptions()
+
+	cmd := &cobra.Command{
+		Use:                   lintUsageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 "Check the migration names and order without a database",
+		TraverseChildren:      true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *LintOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes a new sub command using the specified options.
+func (o *LintOptions) Run(args []string) error {
+	if o.UseRunner() {
+		r, err := runner.NewRunner(o.Verbose, o.Rebuild)
+		if err != nil {
+			return err
+		}
+		return r.RunWithoutDB("lint")
+	}
+
+	return migrate.PrintLint(os.Stdout)
+}
@@ -58,10 +58,8 @@ func (o *RefreshOptions) Run(args []string) error {
 		if err != nil {
 			return err
 		}
-		return r.Run("refresh")
+		return r.Run("refresh", o.RunnerArgs()...)
 	}
 
-	o.Migrator().Refresh()
-
-	return nil
+	return o.Migrator().Refresh()
 }
@@ -1,7 +1,6 @@
 package migrate
 
 import (
-	"github.com/bingo-project/component-base/cli/console"
 	cmdutil "github.com/bingo-project/component-base/cli/util"
 	"github.com/spf13/cobra"
 
@@ -39,14 +38,14 @@ func NewCmdReset() *cobra.Command {
 		},
 	}
 
+	addPretendFlag(cmd)
+
 	return cmd
 }
 
 // Validate makes sure there is no discrepancy in command options.
 func (o *ResetOptions) Validate(cmd *cobra.Command, args []string) error {
-	if o.Production && !o.Force {
-		console.Exit(ErrInProduction.Error())
-	}
+	o.ConfirmProduction()
 
 	return nil
 }
@@ -58,10 +57,8 @@ func (o *ResetOptions) Run(args []string) error {
 		if err != nil {
 			return err
 		}
-		return r.Run("reset")
+		return r.Run("reset", o.RunnerArgs()...)
 	}
 
-	o.Migrator().Reset()
-
-	return nil
+	return o.Migrator().Reset()
 }
@@ -1,7 +1,8 @@
 package migrate
 
 import (
-	"github.com/bingo-project/component-base/cli/console"
+	"strconv"
+
 	cmdutil "github.com/bingo-project/component-base/cli/util"
 	"github.com/spf13/cobra"
 
@@ -15,6 +16,9 @@ const (
 // RollbackOptions is an option struct to support 'rollback' sub command.
 type RollbackOptions struct {
 	*Options
+
+	Step  int
+	Batch int
 }
 
 // NewRollbackOptions returns an initialized RollbackOptions instance.
@@ -39,13 +43,20 @@ func NewCmdRollback() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().IntVar(&o.Step, "step", 0, "Number of migrations to roll back, across batches.")
+	cmd.Flags().IntVar(&o.Batch, "batch", 0, "Roll back the migrations of this batch.")
+	cmd.MarkFlagsMutuallyExclusive("step", "batch")
+	addPretendFlag(cmd)
+
 	return cmd
 }
 
 // Validate makes sure there is no discrepancy in command options.
 func (o *RollbackOptions) Validate(cmd *cobra.Command, args []string) error {
-	if o.Production && !o.Force {
-		console.Exit(ErrInProduction.Error())
+	o.ConfirmProduction()
+
+	if o.Step < 0 || o.Batch < 0 {
+		return cmdutil.UsageErrorf(cmd, "--step and --batch must be positive")
 	}
 
 	return nil
@@ -58,10 +69,15 @@ func (o *RollbackOptions) Run(args []string) error {
 		if err != nil {
 			return err
 		}
-		return r.Run("rollback")
+		return r.Run("rollback", o.RunnerArgs("--step", strconv.Itoa(o.Step), "--batch", strconv.Itoa(o.Batch))...)
 	}
 
-	o.Migrator().Rollback()
-
-	return nil
+	switch {
+	case o.Batch > 0:
+		return o.Migrator().RollbackBatch(o.Batch)
+	case o.Step > 0:
+		return o.Migrator().RollbackSteps(o.Step)
+	default:
+		return o.Migrator().Rollback()
+	}
 }
@@ -0,0 +1,72 @@
+package migrate
+
+import (
+	"os"
+
+	cmdutil "github.com/bingo-project/component-base/cli/util"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/migrate"
+	"github.com/bingo-project/bingoctl/pkg/migrate/runner"
+)
+
+const (
+	statusUsageStr = "status"
+)
+
+// StatusOptions is an option struct to support 'status' sub command.
+type StatusOptions struct {
+	*Options
+
+	Output string
+}
+
+// NewStatusOptions returns an initialized StatusOptions instance.
+func NewStatusOptions() *StatusOptions {
+	return &StatusOptions{
+		Options: opt,
+		Output:  migrate.OutputTable,
+	}
+}
+
+// NewCmdStatus returns new initialized instance of 'status' sub command.
+func NewCmdStatus() *cobra.Command {
+	o := NewStatusOptions()
+
+	cmd := &cobra.Command{
+		Use:                   statusUsageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 "Show the status of each migration",
+		TraverseChildren:      true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format: table or json.")
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *StatusOptions) Validate(cmd *cobra.Command, args []string) error {
+	if o.Output != migrate.OutputTable && o.Output != migrate.OutputJSON {
+		return cmdutil.UsageErrorf(cmd, "unsupported output format %q, use table or json", o.Output)
+	}
+
+	return nil
+}
+
+// Run executes a new sub command using the specified options.
+func (o *StatusOptions) Run(args []string) error {
+	if o.UseRunner() {
+		r, err := runner.NewRunner(o.Verbose, o.Rebuild)
+		if err != nil {
+			return err
+		}
+		return r.Run("status", "--output", o.Output)
+	}
+
+	return o.Migrator().PrintStatus(os.Stdout, o.Output)
+}
@@ -1,7 +1,8 @@
 package migrate
 
 import (
-	"github.com/bingo-project/component-base/cli/console"
+	"strconv"
+
 	cmdutil "github.com/bingo-project/component-base/cli/util"
 	"github.com/spf13/cobra"
 
@@ -15,6 +16,8 @@ const (
 // UpOptions is an option struct to support 'up' sub command.
 type UpOptions struct {
 	*Options
+
+	Step int
 }
 
 // NewUpOptions returns an initialized UpOptions instance.
@@ -39,13 +42,19 @@ func NewCmdUp() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().IntVar(&o.Step, "step", 0, "Number of pending migrations to run, all of them if 0.")
+	cmd.Flags().BoolVar(&o.AllowOutOfOrder, "allow-out-of-order", false, "Run pending migrations older than the last ran migration.")
+	addPretendFlag(cmd)
+
 	return cmd
 }
 
 // Validate makes sure there is no discrepancy in command options.
 func (o *UpOptions) Validate(cmd *cobra.Command, args []string) error {
-	if o.Production && !o.Force {
-		console.Exit(ErrInProduction.Error())
+	o.ConfirmProduction()
+
+	if o.Step < 0 {
+		return cmdutil.UsageErrorf(cmd, "--step must be positive")
 	}
 
 	return nil
@@ -58,10 +67,8 @@ func (o *UpOptions) Run(args []string) error {
 		if err != nil {
 			return err
 		}
-		return r.Run("up")
+		return r.Run("up", o.RunnerArgs("--step", strconv.Itoa(o.Step))...)
 	}
 
-	o.Migrator().Up()
-
-	return nil
+	return o.Migrator().UpSteps(o.Step)
 }
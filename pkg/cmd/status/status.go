@@ -0,0 +1,155 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	cmdutil "github.com/bingo-project/component-base/cli/util"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/generator"
+)
+
+const statusExample = `# List generated files modified by hand, deleted or generated from outdated templates
+bingo status
+
+# Include files unchanged since they were generated
+bingo status --all`
+
+// Options is a struct to support status command.
+type Options struct {
+	All bool
+
+	Out io.Writer
+}
+
+// NewOptions returns an initialized Options instance.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// NewCmdStatus returns a cobra command for listing the state of generated files.
+func NewCmdStatus() *cobra.Command {
+	o := NewOptions()
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Show the state of generated files",
+		Long:    "Compare the files recorded in " + generator.ManifestPath + " with their content on disk and the current templates",
+		Example: statusExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.All, "all", "a", false, "Include files unchanged since they were generated.")
+
+	return cmd
+}
+
+func (o *Options) Complete(cmd *cobra.Command, args []string) (err error) {
+	o.Out = cmd.OutOrStdout()
+
+	return
+}
+
+// Validate makes sure there is no discrepancy in command options.
+func (o *Options) Validate(cmd *cobra.Command, args []string) (err error) {
+	return
+}
+
+// Run executes the status command using the specified options.
+func (o *Options) Run(args []string) error {
+	manifest, err := generator.LoadManifest(generator.ManifestPath)
+	if err != nil {
+		return err
+	}
+
+	if len(manifest.Files) == 0 {
+		_, err = fmt.Fprintf(o.Out, "No generated files recorded in %s.\n", generator.ManifestPath)
+
+		return err
+	}
+
+	rows := [][]cell{{{text: "FILE"}, {text: "RESOURCE"}, {text: "STATE"}, {text: "TEMPLATE"}}}
+	for _, path := range manifest.Paths() {
+		entry := manifest.Files[path]
+
+		state, err := manifest.FileState(path)
+		if err != nil {
+			return err
+		}
+
+		stale, err := entry.Stale()
+		if err != nil {
+			return err
+		}
+
+		if state == generator.FilePristine && !stale && !o.All {
+			continue
+		}
+
+		template := cell{text: "up to date"}
+		if stale {
+			template = cell{text: "stale", color: "yellow"}
+		}
+
+		resource := entry.Options.StructName
+		if resource == "" {
+			resource = entry.Options.Service
+		}
+
+		rows = append(rows, []cell{{text: path}, {text: resource}, {text: state}, template})
+	}
+
+	if len(rows) == 1 {
+		_, err = fmt.Fprintln(o.Out, "All generated files are unchanged and up to date.")
+
+		return err
+	}
+
+	return writeTable(o.Out, rows)
+}
+
+// cell is a table cell, colored after padding so color codes don't count in the column widths.
+type cell struct {
+	text  string
+	color string
+}
+
+// writeTable writes rows with the columns aligned and separated by two spaces.
+func writeTable(w io.Writer, rows [][]cell) error {
+	var widths []int
+	for _, row := range rows {
+		for i, c := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+		}
+	}
+
+	for _, row := range rows {
+		var line strings.Builder
+		for i, c := range row {
+			text := c.text
+			if c.color != "" {
+				text = ansi.Color(text, c.color)
+			}
+			line.WriteString(text)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text)+2))
+			}
+		}
+		if _, err := fmt.Fprintln(w, line.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
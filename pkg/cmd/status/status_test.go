@@ -0,0 +1,56 @@
+// ABOUTME: Tests for the bingo status table.
+// ABOUTME: Verifies columns stay aligned when cells are colored.
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mgutz/ansi"
+)
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]cell{
+		{{text: "FILE"}, {text: "STATE"}, {text: "TEMPLATE"}},
+		{{text: "internal/model/post.go"}, {text: "modified", color: "yellow"}, {text: "stale", color: "yellow"}},
+		{{text: "a.go"}, {text: "missing", color: "red"}, {text: "up to date"}},
+	}
+	if err := writeTable(&buf, rows); err != nil {
+		t.Fatalf("writeTable failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", lines)
+	}
+	if !strings.Contains(lines[1], ansi.Color("modified", "yellow")) {
+		t.Errorf("Expected colored state, got %q", lines[1])
+	}
+
+	// Without the color codes, the columns start at the same offsets.
+	for i, line := range lines {
+		lines[i] = stripColors(line)
+	}
+	for _, column := range []string{"STATE", "TEMPLATE"} {
+		offset := strings.Index(lines[0], column)
+		for _, line := range lines[1:] {
+			if line[offset-2:offset] != "  " || line[offset] == ' ' {
+				t.Errorf("Expected %s column at offset %d, got %q", column, offset, line)
+			}
+		}
+	}
+}
+
+// stripColors removes the ANSI color codes of s.
+func stripColors(s string) string {
+	for {
+		start := strings.Index(s, "\033[")
+		if start < 0 {
+			return s
+		}
+		end := strings.IndexByte(s[start:], 'm')
+		s = s[:start] + s[start+end+1:]
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 
 	"github.com/bingo-project/bingoctl/pkg/db"
@@ -12,29 +14,138 @@ var (
 )
 
 type Config struct {
-	Version      string           `mapstructure:"version" json:"version" yaml:"version"`
-	RootPackage  string           `mapstructure:"rootPackage" json:"rootPackage" yaml:"rootPackage"`
-	Directory    Directory        `mapstructure:"directory" json:"directory" yaml:"directory"`
+	Version     string      `mapstructure:"version" json:"version" yaml:"version"`
+	RootPackage string      `mapstructure:"rootPackage" json:"rootPackage" yaml:"rootPackage"`
+	Directory   Directory   `mapstructure:"directory" json:"directory" yaml:"directory"`
+	Database    *db.Options `mapstructure:"database" json:"database" yaml:"database"`
+
+	// Deprecated: use Database instead.
 	MysqlOptions *db.MySQLOptions `mapstructure:"mysql" json:"mysql" yaml:"mysql"`
 
+	// Connections are named databases selected with --connection, DefaultConnection is used without it.
+	Connections       map[string]*Connection `mapstructure:"connections" json:"connections" yaml:"connections"`
+	DefaultConnection string                 `mapstructure:"defaultConnection" json:"defaultConnection" yaml:"defaultConnection"`
+
 	Registries Registries `mapstructure:"registries" json:"registries" yaml:"registries"`
 
 	Migrate MigrateConfig `mapstructure:"migrate" json:"migrate" yaml:"migrate"`
+
+	Seed SeedConfig `mapstructure:"seed" json:"seed" yaml:"seed"`
+
+	Template TemplateConfig `mapstructure:"template" json:"template" yaml:"template"`
+
+	// connection is the connection selected with --connection, DefaultConnection if empty.
+	connection string
+}
+
+// SeedConfig configures db seed.
+type SeedConfig struct {
+	// History records the registered seeders which ran in Table, so later runs skip them.
+	History bool `mapstructure:"history" json:"history" yaml:"history"`
+
+	// Table records the registered seeders which ran, default bingo_seeder.
+	Table string `mapstructure:"table" json:"table" yaml:"table"`
+}
+
+// GetSeedTable returns the seeder history table, empty for the default.
+func (c *Config) GetSeedTable() string {
+	return c.Seed.Table
 }
 
 type MigrateConfig struct {
 	Table string `mapstructure:"table" json:"table" yaml:"table"`
+
+	// LockTimeout is how long to wait for another process running migrations, e.g. 1m.
+	LockTimeout time.Duration `mapstructure:"lockTimeout" json:"lockTimeout" yaml:"lockTimeout"`
 }
 
 const DefaultMigrateTable = "bingo_migration"
 
 func (c *Config) GetMigrateTable() string {
+	if conn := c.GetConnection(); conn != nil && conn.MigrateTable != "" {
+		return conn.MigrateTable
+	}
 	if c.Migrate.Table != "" {
 		return c.Migrate.Table
 	}
 	return DefaultMigrateTable
 }
 
+// GetMigrationDirectory returns the migration directory of the connection, falling back to directory.migration.
+func (c *Config) GetMigrationDirectory() string {
+	if conn := c.GetConnection(); conn != nil && conn.Migration != "" {
+		return conn.Migration
+	}
+
+	return c.Directory.Migration
+}
+
+// GetDatabaseOptions returns the database options of the selected connection, falling back to the
+// database block and the legacy mysql block.
+func (c *Config) GetDatabaseOptions() *db.Options {
+	if conn := c.GetConnection(); conn != nil {
+		return &conn.Options
+	}
+	if c.Database != nil {
+		return c.Database
+	}
+	if c.MysqlOptions != nil {
+		return c.MysqlOptions.Options()
+	}
+
+	return nil
+}
+
+type TemplateConfig struct {
+	Directory string `mapstructure:"directory" json:"directory" yaml:"directory"`
+}
+
+const DefaultTemplateDirectory = ".bingo/templates"
+
+// GetTemplateDirectory returns the project directory of make template overrides.
+func (c *Config) GetTemplateDirectory() string {
+	if c.Template.Directory != "" {
+		return c.Template.Directory
+	}
+	return DefaultTemplateDirectory
+}
+
+// DefaultFactoryDirectory is where model factories are generated if directory.factory isn't set.
+const DefaultFactoryDirectory = "internal/pkg/database/factory"
+
+// GetFactoryDirectory returns the directory of model factories.
+func (c *Config) GetFactoryDirectory() string {
+	if c.Directory.Factory != "" {
+		return c.Directory.Factory
+	}
+
+	return DefaultFactoryDirectory
+}
+
+// Defaults of registries.routerGroup and registries.handlerArgs.
+const (
+	DefaultRouterGroup = "/v1"
+	DefaultHandlerArgs = "store.S, nil"
+)
+
+// GetRouterGroup returns the prefix of the route group handler routes are registered to.
+func (c *Config) GetRouterGroup() string {
+	if c.Registries.RouterGroup != "" {
+		return c.Registries.RouterGroup
+	}
+
+	return DefaultRouterGroup
+}
+
+// GetHandlerArgs returns the default handler constructor arguments of registered routes.
+func (c *Config) GetHandlerArgs() string {
+	if c.Registries.HandlerArgs != "" {
+		return c.Registries.HandlerArgs
+	}
+
+	return DefaultHandlerArgs
+}
+
 type Directory struct {
 	CMD        string `mapstructure:"cmd" json:"cmd" yaml:"cmd"`
 	Model      string `mapstructure:"model" json:"model" yaml:"model"`
@@ -46,12 +157,21 @@ type Directory struct {
 	Job        string `mapstructure:"job" json:"job" yaml:"job"`
 	Migration  string `mapstructure:"migration" json:"migration" yaml:"migration"`
 	Seeder     string `mapstructure:"seeder" json:"seeder" yaml:"seeder"`
+	Factory    string `mapstructure:"factory" json:"factory" yaml:"factory"`
 }
 
 type Registries struct {
-	Router string   `mapstructure:"router" json:"router" yaml:"router"`
-	Store  Registry `mapstructure:"store" json:"store" yaml:"store"`
-	Biz    Registry `mapstructure:"biz" json:"biz" yaml:"biz"`
+	Router string `mapstructure:"router" json:"router" yaml:"router"`
+
+	// RouterGroup is the prefix of the route group handler routes are registered to, default /v1.
+	RouterGroup string `mapstructure:"routerGroup" json:"routerGroup" yaml:"routerGroup"`
+
+	// HandlerArgs are the handler constructor arguments used when the router file has no handler
+	// constructor call to copy them from, default "store.S, nil".
+	HandlerArgs string `mapstructure:"handlerArgs" json:"handlerArgs" yaml:"handlerArgs"`
+
+	Store Registry `mapstructure:"store" json:"store" yaml:"store"`
+	Biz   Registry `mapstructure:"biz" json:"biz" yaml:"biz"`
 }
 
 type Registry struct {
@@ -74,6 +194,7 @@ func NewDefaultConfig() *Config {
 			Job:        "internal/watcher/watcher",
 			Migration:  "internal/pkg/database/migration",
 			Seeder:     "internal/pkg/database/seeder",
+			Factory:    DefaultFactoryDirectory,
 		},
 	}
 }
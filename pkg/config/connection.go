@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/db"
+)
+
+// Connection is a named database with its own migrations.
+type Connection struct {
+	db.Options `mapstructure:",squash" yaml:",inline"`
+
+	// Migration is the migration directory of the connection, directory.migration if empty.
+	Migration string `mapstructure:"migration" json:"migration" yaml:"migration"`
+
+	// MigrateTable is the migration table of the connection, migrate.table if empty.
+	MigrateTable string `mapstructure:"migrateTable" json:"migrateTable" yaml:"migrateTable"`
+}
+
+// UseConnection selects the named connection for the database options and migrations, the default
+// connection if name is empty.
+func (c *Config) UseConnection(name string) error {
+	if _, ok := c.Connections[name]; name != "" && !ok {
+		return fmt.Errorf("connection %q not found in connections of .bingo.yaml", name)
+	}
+
+	c.connection = name
+
+	return nil
+}
+
+// GetConnectionName returns the name of the connection selected with UseConnection, empty for the default one.
+func (c *Config) GetConnectionName() string {
+	if c.connection == c.defaultConnectionName() {
+		return ""
+	}
+
+	return c.connection
+}
+
+// GetConnection returns the selected connection. Without --connection it's DefaultConnection, or the only
+// connection when the database block isn't set. It returns nil to use the database block.
+func (c *Config) GetConnection() *Connection {
+	name := c.connection
+	if name == "" {
+		name = c.defaultConnectionName()
+	}
+
+	return c.Connections[name]
+}
+
+func (c *Config) defaultConnectionName() string {
+	if c.DefaultConnection != "" || c.Database != nil || c.MysqlOptions != nil || len(c.Connections) != 1 {
+		return c.DefaultConnection
+	}
+
+	for name := range c.Connections {
+		return name
+	}
+
+	return ""
+}
+
+// AddConnectionFlag adds the --connection flag to cmd and its sub commands, the connection is selected
+// before they run. The pre-run hooks cmd already has, and the one of its parents cobra would run otherwise,
+// still run: the parent's first, then the connection is selected and cmd's own hook runs.
+func AddConnectionFlag(cmd *cobra.Command) {
+	var name string
+	cmd.PersistentFlags().StringVar(&name, "connection", "", "Database connection of the connections in .bingo.yaml, the default connection if empty.")
+
+	preRunE, preRun := cmd.PersistentPreRunE, cmd.PersistentPreRun
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		// Cobra only runs the nearest hook, which is this one.
+		if err := runParentPreRun(cmd, c, args); err != nil {
+			return err
+		}
+
+		if err := Cfg.UseConnection(name); err != nil {
+			return err
+		}
+
+		if preRunE != nil {
+			return preRunE(c, args)
+		}
+		if preRun != nil {
+			preRun(c, args)
+		}
+
+		return nil
+	}
+	cmd.PersistentPreRun = nil
+}
+
+// runParentPreRun runs the persistent pre-run hook of the nearest parent of cmd having one.
+func runParentPreRun(cmd, c *cobra.Command, args []string) error {
+	for p := cmd.Parent(); p != nil; p = p.Parent() {
+		if p.PersistentPreRunE != nil {
+			return p.PersistentPreRunE(c, args)
+		}
+		if p.PersistentPreRun != nil {
+			p.PersistentPreRun(c, args)
+
+			return nil
+		}
+	}
+
+	return nil
+}
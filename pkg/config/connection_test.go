@@ -0,0 +1,117 @@
+// ABOUTME: Tests for the named database connections of the config
+// ABOUTME: Verifies the default connection, --connection selection, pre-run hooks and per-connection migration settings
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bingo-project/bingoctl/pkg/db"
+)
+
+func newConnectionsConfig() *Config {
+	cfg := NewDefaultConfig()
+	cfg.Migrate.Table = "migrations"
+	cfg.DefaultConnection = "main"
+	cfg.Connections = map[string]*Connection{
+		"main": {Options: db.Options{Driver: db.DriverMySQL, Database: "main"}},
+		"analytics": {
+			Options:      db.Options{Driver: db.DriverPostgres, Database: "analytics"},
+			Migration:    "internal/pkg/database/analytics",
+			MigrateTable: "analytics_migration",
+		},
+	}
+
+	return cfg
+}
+
+func TestConnection_DefaultConnection(t *testing.T) {
+	cfg := newConnectionsConfig()
+
+	if got := cfg.GetDatabaseOptions().Database; got != "main" {
+		t.Errorf("database = %s, want main", got)
+	}
+	if got := cfg.GetMigrationDirectory(); got != "internal/pkg/database/migration" {
+		t.Errorf("migration directory = %s, want directory.migration", got)
+	}
+	if got := cfg.GetMigrateTable(); got != "migrations" {
+		t.Errorf("migrate table = %s, want migrate.table", got)
+	}
+	if err := cfg.UseConnection("main"); err != nil || cfg.GetConnectionName() != "" {
+		t.Errorf("UseConnection(main) = %v, connection name %q, want the default connection", err, cfg.GetConnectionName())
+	}
+}
+
+func TestConnection_UseConnection(t *testing.T) {
+	cfg := newConnectionsConfig()
+
+	if err := cfg.UseConnection("analytics"); err != nil {
+		t.Fatalf("UseConnection() failed: %v", err)
+	}
+
+	if got := cfg.GetDatabaseOptions().Database; got != "analytics" {
+		t.Errorf("database = %s, want analytics", got)
+	}
+	if got := cfg.GetMigrationDirectory(); got != "internal/pkg/database/analytics" {
+		t.Errorf("migration directory = %s", got)
+	}
+	if got := cfg.GetMigrateTable(); got != "analytics_migration" {
+		t.Errorf("migrate table = %s", got)
+	}
+	if got := cfg.GetConnectionName(); got != "analytics" {
+		t.Errorf("connection name = %s, want analytics", got)
+	}
+
+	if err := cfg.UseConnection("unknown"); err == nil {
+		t.Error("UseConnection() expected error for an unknown connection")
+	}
+}
+
+func TestConnection_FallsBackToDatabaseBlock(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Database = &db.Options{Driver: db.DriverSQLite, Database: "app.db"}
+	if got := cfg.GetDatabaseOptions(); got != cfg.Database {
+		t.Errorf("database options = %+v, want the database block", got)
+	}
+
+	// A single connection is the default one without the database block.
+	cfg = NewDefaultConfig()
+	cfg.Connections = map[string]*Connection{"only": {Options: db.Options{Database: "only"}}}
+	if got := cfg.GetDatabaseOptions(); got == nil || got.Database != "only" {
+		t.Errorf("database options = %+v, want the only connection", got)
+	}
+}
+
+func TestAddConnectionFlag_KeepsPreRunHooks(t *testing.T) {
+	original := Cfg
+	Cfg = newConnectionsConfig()
+	t.Cleanup(func() { Cfg = original })
+
+	var calls []string
+	root := &cobra.Command{
+		Use:              "root",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) { calls = append(calls, "root") },
+	}
+	parent := &cobra.Command{
+		Use: "db",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			calls = append(calls, "db "+Cfg.GetDatabaseOptions().Database)
+			return nil
+		},
+	}
+	AddConnectionFlag(parent)
+	root.AddCommand(parent)
+	parent.AddCommand(&cobra.Command{Use: "seed", Run: func(cmd *cobra.Command, args []string) { calls = append(calls, "seed") }})
+
+	root.SetArgs([]string{"db", "seed", "--connection", "analytics"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if got := strings.Join(calls, ","); got != "root,db analytics,seed" {
+		t.Errorf("calls = %s, want the root and db hooks run with the analytics connection", got)
+	}
+}
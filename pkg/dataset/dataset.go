@@ -0,0 +1,102 @@
+// Package dataset exports the rows of database tables to SQL, JSON and CSV files and imports them back.
+package dataset
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Formats of exported files.
+const (
+	FormatSQL  = "sql"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// DefaultBatchSize is the number of rows written or inserted at a time.
+const DefaultBatchSize = 500
+
+// csvNull is the CSV value of NULL, as in fixtures.
+const csvNull = `\N`
+
+// columnKind is the kind of values a column holds, telling how to write and read them.
+type columnKind int
+
+const (
+	kindText columnKind = iota
+	kindNumber
+	kindBool
+	kindTime
+	kindBinary
+)
+
+// FormatOf returns the format of file by its extension, e.g. json for users.json.
+func FormatOf(file string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(file), "."))
+}
+
+// CheckFormat returns an error if format isn't supported.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatSQL, FormatJSON, FormatCSV:
+		return nil
+	}
+
+	return fmt.Errorf("unsupported format %q, use %s, %s or %s", format, FormatSQL, FormatJSON, FormatCSV)
+}
+
+// kindOf returns the kind of a column by its database type name, e.g. VARCHAR or bigint.
+func kindOf(typeName string) columnKind {
+	typeName = strings.ToUpper(typeName)
+
+	switch {
+	case strings.Contains(typeName, "BLOB"), strings.Contains(typeName, "BINARY"), typeName == "BYTEA":
+		return kindBinary
+	case strings.Contains(typeName, "DATE"), strings.Contains(typeName, "TIME"):
+		return kindTime
+	case strings.HasPrefix(typeName, "BOOL"):
+		return kindBool
+	case strings.Contains(typeName, "INT"), strings.Contains(typeName, "DEC"), strings.Contains(typeName, "NUMERIC"),
+		strings.Contains(typeName, "FLOAT"), strings.Contains(typeName, "DOUBLE"), strings.Contains(typeName, "REAL"),
+		strings.Contains(typeName, "SERIAL"):
+		return kindNumber
+	}
+
+	return kindText
+}
+
+// tableColumns returns the kinds of the columns of table by name, and its single primary key if any.
+func tableColumns(db *gorm.DB, table string) (map[string]columnKind, string, error) {
+	columnTypes, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(columnTypes) == 0 {
+		return nil, "", fmt.Errorf("table %s not found", table)
+	}
+
+	var keys []string
+	kinds := make(map[string]columnKind, len(columnTypes))
+	for _, columnType := range columnTypes {
+		kinds[columnType.Name()] = kindOf(columnType.DatabaseTypeName())
+		if primary, ok := columnType.PrimaryKey(); ok && primary {
+			keys = append(keys, columnType.Name())
+		}
+	}
+	if len(keys) != 1 {
+		return kinds, "", nil
+	}
+
+	return kinds, keys[0], nil
+}
+
+// quote quotes the identifier name for the dialect of db.
+func quote(db *gorm.DB, name string) string {
+	var builder strings.Builder
+	db.Dialector.QuoteTo(&builder, name)
+
+	return builder.String()
+}
@@ -0,0 +1,194 @@
+// ABOUTME: Tests for exporting table rows to SQL, JSON and CSV and importing them back.
+// ABOUTME: Verifies the formats round trip NULLs, quotes, binary and time values, and tables are ordered by foreign keys.
+package dataset
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type datasetUser struct {
+	ID        uint
+	Name      string
+	Bio       *string
+	Avatar    []byte
+	Active    bool
+	Score     float64
+	CreatedAt time.Time
+}
+
+// setupDatabase returns a database with an empty users table.
+func setupDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	if err := db.Table("users").AutoMigrate(&datasetUser{}); err != nil {
+		t.Fatalf("Failed to migrate users: %v", err)
+	}
+
+	return db
+}
+
+func testUsers() []datasetUser {
+	bio := "It's a \"quote\", a back\\slash;\nand a new line"
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+
+	return []datasetUser{
+		{ID: 1, Name: "Alice", Bio: &bio, Avatar: []byte{0, 1, 0xfe}, Active: true, Score: 1.5, CreatedAt: createdAt},
+		{ID: 2, Name: "Bob", Active: false, Score: 2, CreatedAt: createdAt},
+		{ID: 3, Name: "Carol", Active: true, Score: 3, CreatedAt: createdAt},
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []string{FormatSQL, FormatJSON, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			source := setupDatabase(t)
+			source.Table("users").Create(testUsers())
+
+			var buf bytes.Buffer
+			count, err := Export(source, &buf, "users", ExportOptions{Format: format, Where: "id < 3", BatchSize: 1})
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			if count != 2 {
+				t.Fatalf("Expected 2 exported rows, got %d:\n%s", count, buf.String())
+			}
+
+			target := setupDatabase(t)
+			count, err = Import(target, &buf, "users", ImportOptions{Format: format})
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if count != 2 {
+				t.Errorf("Expected 2 imported rows, got %d", count)
+			}
+
+			var users []datasetUser
+			target.Table("users").Order("id").Find(&users)
+			want := testUsers()[:2]
+			if len(users) != len(want) {
+				t.Fatalf("Expected %d users, got %+v", len(want), users)
+			}
+			for i := range want {
+				got := users[i]
+				if got.Name != want[i].Name || got.Active != want[i].Active || got.Score != want[i].Score ||
+					!bytes.Equal(got.Avatar, want[i].Avatar) || !got.CreatedAt.Equal(want[i].CreatedAt) {
+					t.Errorf("User %d = %+v, want %+v", i, got, want[i])
+				}
+				if (got.Bio == nil) != (want[i].Bio == nil) || got.Bio != nil && *got.Bio != *want[i].Bio {
+					t.Errorf("User %d bio = %v, want %v", i, got.Bio, want[i].Bio)
+				}
+			}
+		})
+	}
+}
+
+func TestExport_Limit(t *testing.T) {
+	db := setupDatabase(t)
+	db.Table("users").Create(testUsers())
+
+	var buf bytes.Buffer
+	count, err := Export(db, &buf, "users", ExportOptions{Format: FormatCSV, Limit: 2})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if count != 2 || strings.Contains(buf.String(), "Carol") {
+		t.Errorf("Expected Alice and Bob, got %d rows:\n%s", count, buf.String())
+	}
+	if !strings.Contains(buf.String(), "\n2,Bob,\\N,") {
+		t.Errorf("Expected Bob with a NULL bio, got:\n%s", buf.String())
+	}
+}
+
+func TestExport_Errors(t *testing.T) {
+	db := setupDatabase(t)
+
+	if _, err := Export(db, &bytes.Buffer{}, "users", ExportOptions{Format: "xml"}); err == nil {
+		t.Error("Expected error for an unsupported format")
+	}
+	if _, err := Export(db, &bytes.Buffer{}, "missing", ExportOptions{Format: FormatJSON}); err == nil {
+		t.Error("Expected error for a missing table")
+	}
+}
+
+func TestImport_RollsBack(t *testing.T) {
+	db := setupDatabase(t)
+
+	rows := `[{"id": 1, "name": "Alice"}, {"id": 1, "name": "Again"}]`
+	if _, err := Import(db, strings.NewReader(rows), "users", ImportOptions{Format: FormatJSON, BatchSize: 1}); err == nil {
+		t.Fatal("Expected duplicate primary key error")
+	}
+
+	var count int64
+	db.Table("users").Count(&count)
+	if count != 0 {
+		t.Errorf("Expected the import to be rolled back, got %d rows", count)
+	}
+}
+
+func TestSQLLiteral(t *testing.T) {
+	tests := []struct {
+		dialect string
+		value   any
+		want    string
+	}{
+		{"mysql", nil, "NULL"},
+		{"mysql", true, "1"},
+		{"postgres", true, "TRUE"},
+		{"mysql", `it's a\b`, `'it''s a\\b'`},
+		{"postgres", `a\b`, `E'a\\b'`},
+		{"sqlite", `a\b`, `'a' || char(92) || 'b'`},
+		{"mysql", []byte{0xca, 0xfe}, "X'cafe'"},
+		{"postgres", []byte{0xca, 0xfe}, `'\xcafe'`},
+		{"mysql", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "'2024-01-02 03:04:05'"},
+		{"postgres", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "'2024-01-02 03:04:05+00:00'"},
+	}
+
+	for _, tt := range tests {
+		if got := sqlLiteral(tt.dialect, tt.value); got != tt.want {
+			t.Errorf("sqlLiteral(%s, %v) = %s, want %s", tt.dialect, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSortTables(t *testing.T) {
+	db := setupDatabase(t)
+	for _, statement := range []string{
+		"CREATE TABLE posts (id integer primary key, user_id integer REFERENCES users (id), parent_id integer REFERENCES posts (id))",
+		"CREATE TABLE comments (id integer primary key, post_id integer REFERENCES posts (id), user_id integer REFERENCES users (id))",
+		"CREATE TABLE tags (id integer primary key)",
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+
+	got, err := SortTables(db, []string{"comments", "posts", "tags", "users"})
+	if err != nil {
+		t.Fatalf("SortTables failed: %v", err)
+	}
+	if want := "users,posts,comments,tags"; strings.Join(got, ",") != want {
+		t.Errorf("SortTables = %v, want %s", got, want)
+	}
+}
+
+func TestImportSQL_Tables(t *testing.T) {
+	db := setupDatabase(t)
+
+	script := "INSERT INTO \"users\" (id, name) VALUES (1, 'a');\ninsert into users(id, name) values (2, 'b');\nUPDATE users SET name = 'c';\n"
+	count, tables, err := importSQL(db, strings.NewReader(script))
+	if err != nil {
+		t.Fatalf("importSQL failed: %v", err)
+	}
+	if count != 4 || strings.Join(tables, ",") != "users" {
+		t.Errorf("importSQL = %d rows into %v, want 4 rows into users", count, tables)
+	}
+}
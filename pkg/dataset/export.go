@@ -0,0 +1,390 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExportOptions defines the rows to export and how to write them.
+type ExportOptions struct {
+	Format string
+
+	// Where is a SQL condition the exported rows match, e.g. "status = 1".
+	Where string
+
+	// Limit is the maximum number of rows to export, 0 for all.
+	Limit int
+
+	BatchSize int
+}
+
+// rowWriter writes exported rows in a format.
+type rowWriter interface {
+	begin(columns []string) error
+	write(rows [][]any) error
+	end() error
+}
+
+// Export writes the rows of table matching opts to w and returns their number. The rows are read with a
+// cursor ordered by the primary key and written in batches, so tables needn't fit in memory.
+func Export(db *gorm.DB, w io.Writer, table string, opts ExportOptions) (int, error) {
+	if err := CheckFormat(opts.Format); err != nil {
+		return 0, err
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	_, primaryKey, err := tableColumns(db, table)
+	if err != nil {
+		return 0, err
+	}
+
+	query := db.Table(table)
+	if opts.Where != "" {
+		query = query.Where(opts.Where)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	if primaryKey != "" {
+		query = query.Order(quote(db, primaryKey))
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+	columns := make([]string, len(columnTypes))
+	kinds := make([]columnKind, len(columnTypes))
+	for i, columnType := range columnTypes {
+		columns[i], kinds[i] = columnType.Name(), kindOf(columnType.DatabaseTypeName())
+	}
+
+	buf := bufio.NewWriter(w)
+	var writer rowWriter
+	switch opts.Format {
+	case FormatSQL:
+		writer = &sqlWriter{w: buf, db: db, table: table}
+	case FormatJSON:
+		writer = &jsonWriter{w: buf}
+	case FormatCSV:
+		writer = &csvWriter{w: csv.NewWriter(buf)}
+	}
+
+	if err := writer.begin(columns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	batch := make([][]any, 0, opts.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := writer.write(batch); err != nil {
+			return err
+		}
+		count += len(batch)
+		batch = batch[:0]
+
+		return buf.Flush()
+	}
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return count, err
+		}
+		for i, value := range values {
+			values[i] = normalize(value, kinds[i])
+		}
+
+		batch = append(batch, values)
+		if len(batch) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	if err := flush(); err != nil {
+		return count, err
+	}
+	if err := writer.end(); err != nil {
+		return count, err
+	}
+
+	return count, buf.Flush()
+}
+
+// normalize converts the raw bytes some drivers scan text and numbers into to strings and json numbers.
+func normalize(value any, kind columnKind) any {
+	b, ok := value.([]byte)
+	if !ok || kind == kindBinary {
+		return value
+	}
+
+	s := string(b)
+	switch kind {
+	case kindNumber:
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(s)
+		}
+	case kindBool:
+		if v, err := strconv.ParseBool(s); err == nil {
+			return v
+		}
+	}
+
+	return s
+}
+
+// sqlWriter writes rows as an INSERT statement per batch.
+type sqlWriter struct {
+	w       *bufio.Writer
+	db      *gorm.DB
+	table   string
+	columns string
+}
+
+func (s *sqlWriter) begin(columns []string) error {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quote(s.db, column)
+	}
+	s.columns = strings.Join(quoted, ", ")
+
+	_, err := fmt.Fprintf(s.w, "-- Rows of %s exported by bingo db export.\n\n", s.table)
+
+	return err
+}
+
+func (s *sqlWriter) write(rows [][]any) error {
+	fmt.Fprintf(s.w, "INSERT INTO %s (%s) VALUES\n", quote(s.db, s.table), s.columns)
+	for i, row := range rows {
+		literals := make([]string, len(row))
+		for j, value := range row {
+			literals[j] = sqlLiteral(s.db.Dialector.Name(), value)
+		}
+
+		end := ",\n"
+		if i == len(rows)-1 {
+			end = ";\n"
+		}
+		if _, err := fmt.Fprintf(s.w, "(%s)%s", strings.Join(literals, ", "), end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *sqlWriter) end() error {
+	return nil
+}
+
+// sqlLiteral returns value as a SQL literal of dialect.
+func sqlLiteral(dialect string, value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if dialect == "postgres" {
+			return strings.ToUpper(strconv.FormatBool(v))
+		}
+		if v {
+			return "1"
+		}
+
+		return "0"
+	case json.Number:
+		return v.String()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		if dialect == "postgres" {
+			return `'\x` + hex.EncodeToString(v) + "'"
+		}
+
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		// MySQL rejects zone offsets before 8.0.19, the connection time zone applies.
+		if dialect == "mysql" {
+			return sqlString(dialect, v.Format("2006-01-02 15:04:05.999999"))
+		}
+
+		return sqlString(dialect, v.Format("2006-01-02 15:04:05.999999999-07:00"))
+	case string:
+		return sqlString(dialect, v)
+	}
+
+	return sqlString(dialect, fmt.Sprint(value))
+}
+
+// sqlString returns s quoted as a SQL string of dialect. Backslashes are escaped as the SQL splitter of
+// bingo reads them as escapes in any dialect.
+func sqlString(dialect, s string) string {
+	quoted := "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	if !strings.Contains(s, `\`) {
+		return quoted
+	}
+
+	switch dialect {
+	case "mysql":
+		return strings.ReplaceAll(quoted, `\`, `\\`)
+	case "postgres":
+		return "E" + strings.ReplaceAll(quoted, `\`, `\\`)
+	}
+
+	// SQLite strings have no escapes.
+	return strings.ReplaceAll(quoted, `\`, `' || char(92) || '`)
+}
+
+// jsonWriter writes rows as a JSON array of objects, keeping the order of the columns.
+type jsonWriter struct {
+	w       *bufio.Writer
+	columns [][]byte
+	rows    int
+}
+
+func (j *jsonWriter) begin(columns []string) error {
+	j.columns = make([][]byte, len(columns))
+	for i, column := range columns {
+		encoded, err := marshal(column)
+		if err != nil {
+			return err
+		}
+		j.columns[i] = encoded
+	}
+
+	_, err := j.w.WriteString("[")
+
+	return err
+}
+
+func (j *jsonWriter) write(rows [][]any) error {
+	for _, row := range rows {
+		if j.rows > 0 {
+			j.w.WriteString(",")
+		}
+		j.rows++
+
+		j.w.WriteString("\n  {")
+		for i, value := range row {
+			if i > 0 {
+				j.w.WriteString(", ")
+			}
+			if t, ok := value.(time.Time); ok {
+				value = t.Format(time.RFC3339Nano)
+			}
+
+			encoded, err := marshal(value)
+			if err != nil {
+				return err
+			}
+			j.w.Write(j.columns[i])
+			j.w.WriteString(": ")
+			j.w.Write(encoded)
+		}
+		if _, err := j.w.WriteString("}"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (j *jsonWriter) end() error {
+	if j.rows > 0 {
+		j.w.WriteString("\n")
+	}
+	_, err := j.w.WriteString("]\n")
+
+	return err
+}
+
+// marshal encodes value as JSON without escaping HTML characters.
+func marshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// csvWriter writes rows as CSV records after a header of the columns.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) begin(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) write(rows [][]any) error {
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = csvValue(value)
+		}
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+func (c *csvWriter) end() error {
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+// csvValue returns value as a CSV field, \N for NULL and base64 for binary values as in JSON.
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return csvNull
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
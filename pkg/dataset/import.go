@@ -0,0 +1,233 @@
+package dataset
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bingo-project/bingoctl/pkg/db"
+	"github.com/bingo-project/bingoctl/pkg/migrate"
+)
+
+// insertTableReg matches the table of an INSERT statement, quoted or not.
+var insertTableReg = regexp.MustCompile("(?i)^\\s*INSERT\\s+INTO\\s+(`[^`]+`|\"[^\"]+\"|[^\\s(]+)")
+
+// ImportOptions defines how to read the imported rows.
+type ImportOptions struct {
+	Format    string
+	BatchSize int
+}
+
+// Import inserts the rows read from r into table in batches and returns their number, all or none of them
+// in a transaction. SQL scripts are executed statement by statement and name their tables themselves.
+// On PostgreSQL, the sequences of the primary keys are moved past the imported ids afterwards.
+func Import(db *gorm.DB, r io.Reader, table string, opts ImportOptions) (count int, err error) {
+	if err := CheckFormat(opts.Format); err != nil {
+		return 0, err
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if opts.Format == FormatSQL {
+			var tables []string
+			count, tables, err = importSQL(tx, r)
+			if err != nil {
+				return err
+			}
+
+			return resetSequences(tx, tables)
+		}
+
+		kinds, _, err := tableColumns(tx, table)
+		if err != nil {
+			return err
+		}
+
+		var batch []map[string]any
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := tx.Table(table).Create(batch).Error; err != nil {
+				return err
+			}
+			count += len(batch)
+			batch = nil
+
+			return nil
+		}
+		add := func(row map[string]any) error {
+			for column, value := range row {
+				row[column] = parseValue(value, kinds[column])
+			}
+			batch = append(batch, row)
+			if len(batch) < opts.BatchSize {
+				return nil
+			}
+
+			return flush()
+		}
+
+		if opts.Format == FormatJSON {
+			err = readJSON(r, add)
+		} else {
+			err = readCSV(r, add)
+		}
+		if err != nil {
+			return err
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		return resetSequences(tx, []string{table})
+	})
+
+	return count, err
+}
+
+// importSQL executes the statements of the script read from r and returns the number of affected rows
+// and the tables rows were inserted into.
+func importSQL(tx *gorm.DB, r io.Reader) (int, []string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	statements, err := migrate.SplitSQL(string(content))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		count  int64
+		tables []string
+	)
+	for _, statement := range statements {
+		result := tx.Exec(statement)
+		if result.Error != nil {
+			return 0, nil, result.Error
+		}
+		count += result.RowsAffected
+
+		if match := insertTableReg.FindStringSubmatch(statement); match != nil {
+			table := strings.Trim(match[1], "`\"")
+			if !slices.Contains(tables, table) {
+				tables = append(tables, table)
+			}
+		}
+	}
+
+	return int(count), tables, nil
+}
+
+// resetSequences moves the PostgreSQL sequences of the primary keys of tables past their largest ids.
+func resetSequences(tx *gorm.DB, tables []string) error {
+	if tx.Dialector.Name() != db.DriverPostgres {
+		return nil
+	}
+
+	for _, table := range tables {
+		_, primaryKey, err := tableColumns(tx, table)
+		if err != nil {
+			return err
+		}
+		if primaryKey == "" {
+			continue
+		}
+		if err := db.ResetSequence(tx, table, primaryKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readJSON calls add with each object of the JSON array read from r.
+func readJSON(r io.Reader, add func(map[string]any) error) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return errors.New("expected a JSON array of rows")
+	}
+	for decoder.More() {
+		var row map[string]any
+		if err := decoder.Decode(&row); err != nil {
+			return err
+		}
+		if err := add(row); err != nil {
+			return err
+		}
+	}
+
+	_, err := decoder.Token()
+
+	return err
+}
+
+// readCSV calls add with each record read from r, keyed by the columns of the header.
+func readCSV(r io.Reader, add func(map[string]any) error) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		row := make(map[string]any, len(header))
+		for i, column := range header {
+			if record[i] == csvNull {
+				row[column] = nil
+			} else {
+				row[column] = record[i]
+			}
+		}
+		if err := add(row); err != nil {
+			return err
+		}
+	}
+}
+
+// parseValue converts an imported value to the kind of its column: binary values are base64 decoded,
+// times parsed and nested JSON values encoded.
+func parseValue(value any, kind columnKind) any {
+	switch v := value.(type) {
+	case string:
+		switch kind {
+		case kindBinary:
+			if b, err := base64.StdEncoding.DecodeString(v); err == nil {
+				return b
+			}
+		case kindTime:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		}
+	case map[string]any, []any:
+		if encoded, err := json.Marshal(v); err == nil {
+			return string(encoded)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,74 @@
+package dataset
+
+import (
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// SortTables returns tables sorted so that every table comes after the tables it references with foreign
+// keys, so their rows can be imported in order. Tables without references between them keep their order,
+// cycles of tables referencing each other are broken where they are found.
+func SortTables(db *gorm.DB, tables []string) ([]string, error) {
+	references := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		referenced, err := referencedTables(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("reading foreign keys of %s: %w", table, err)
+		}
+		references[table] = referenced
+	}
+
+	var (
+		sorted  []string
+		visited = make(map[string]bool, len(tables))
+	)
+
+	var visit func(table string)
+	visit = func(table string) {
+		if visited[table] {
+			return
+		}
+		visited[table] = true
+
+		for _, referenced := range references[table] {
+			if slices.Contains(tables, referenced) {
+				visit(referenced)
+			}
+		}
+		sorted = append(sorted, table)
+	}
+
+	for _, table := range tables {
+		visit(table)
+	}
+
+	return sorted, nil
+}
+
+// referencedTables returns the tables table references with foreign keys, itself excluded.
+func referencedTables(db *gorm.DB, table string) ([]string, error) {
+	var query string
+	switch db.Dialector.Name() {
+	case "mysql":
+		query = `SELECT DISTINCT REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL`
+	case "postgres":
+		query = `SELECT DISTINCT r.relname FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_class r ON r.oid = c.confrelid
+			WHERE c.contype = 'f' AND n.nspname = CURRENT_SCHEMA() AND t.relname = ?`
+	case "sqlite":
+		query = `SELECT DISTINCT "table" FROM pragma_foreign_key_list(?)`
+	default:
+		return nil, nil
+	}
+
+	var referenced []string
+	if err := db.Raw(query, table).Scan(&referenced).Error; err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(referenced, func(name string) bool { return name == table }), nil
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Supported database drivers.
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// ErrNoConfig is returned when no database configuration is provided.
+var ErrNoConfig = errors.New("database configuration not found in .bingo.yaml")
+
+// Options defines driver-agnostic options for database connection.
+// For sqlite, Database is the path of the database file and the other fields are ignored.
+type Options struct {
+	Driver   string `mapstructure:"driver" json:"driver" yaml:"driver"`
+	Host     string `mapstructure:"host" json:"host" yaml:"host"`
+	Username string `mapstructure:"username" json:"username" yaml:"username"`
+	Password string `mapstructure:"password" json:"-" yaml:"password"`
+	Database string `mapstructure:"database" json:"database" yaml:"database"`
+	SSLMode  string `mapstructure:"sslMode" json:"sslMode" yaml:"sslMode"`
+}
+
+// GetDriver returns the configured driver, default: mysql.
+func (o *Options) GetDriver() string {
+	if o.Driver == "" {
+		return DriverMySQL
+	}
+
+	return o.Driver
+}
+
+// DSN returns the data source name of the configured driver.
+func (o *Options) DSN() string {
+	switch o.GetDriver() {
+	case DriverPostgres:
+		return o.postgresDSN()
+	case DriverSQLite:
+		return o.sqliteDSN()
+	default:
+		return o.mysqlDSN()
+	}
+}
+
+// Validate checks the options are complete for the configured driver.
+func (o *Options) Validate() error {
+	if _, err := o.Dialector(); err != nil {
+		return err
+	}
+
+	if o.GetDriver() == DriverSQLite && o.Database == "" {
+		return errors.New("database file path is required for sqlite driver")
+	}
+
+	return nil
+}
+
+// Dialector returns the gorm dialector of the configured driver.
+func (o *Options) Dialector() (gorm.Dialector, error) {
+	switch o.GetDriver() {
+	case DriverMySQL:
+		return mysql.Open(o.DSN()), nil
+	case DriverPostgres:
+		return postgres.Open(o.DSN()), nil
+	case DriverSQLite:
+		return sqlite.Open(o.DSN()), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", o.Driver)
+	}
+}
+
+// NewDB create a new gorm db instance with the given options.
+func NewDB(opts *Options) (*gorm.DB, error) {
+	if opts == nil {
+		return nil, ErrNoConfig
+	}
+
+	dialector, err := opts.Dialector()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.GetDriver() == DriverSQLite {
+		if err := ensureSQLiteDir(opts.Database); err != nil {
+			return nil, err
+		}
+	}
+
+	// Log errors to stderr, stdout is kept for output parsed by callers, e.g. migrate status --output json.
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.New(log.New(os.Stderr, "\r\n", log.LstdFlags), logger.Config{
+			SlowThreshold: 200 * time.Millisecond,
+			LogLevel:      logger.Error,
+			Colorful:      true,
+		}),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// SetMaxOpenConns sets the maximum number of open connections to the database.
+	// SQLite allows a single writer only, so share one connection to avoid "database is locked".
+	sqlDB.SetMaxOpenConns(100)
+	if opts.GetDriver() == DriverSQLite {
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
+	sqlDB.SetConnMaxLifetime(10)
+
+	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
+	sqlDB.SetMaxIdleConns(100)
+
+	return db, nil
+}
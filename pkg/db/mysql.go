@@ -3,12 +3,12 @@ package db
 import (
 	"fmt"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // MySQLOptions defines options for mysql database.
+//
+// Deprecated: use Options with Driver set to DriverMySQL.
 type MySQLOptions struct {
 	Host     string `mapstructure:"host" json:"host" yaml:"host"`
 	Username string `mapstructure:"username" json:"username" yaml:"username"`
@@ -16,8 +16,23 @@ type MySQLOptions struct {
 	Database string `mapstructure:"database" json:"database" yaml:"database"`
 }
 
+// Options converts mysql options to driver-agnostic options.
+func (o *MySQLOptions) Options() *Options {
+	return &Options{
+		Driver:   DriverMySQL,
+		Host:     o.Host,
+		Username: o.Username,
+		Password: o.Password,
+		Database: o.Database,
+	}
+}
+
 // DSN returns mysql dsn.
 func (o *MySQLOptions) DSN() string {
+	return o.Options().DSN()
+}
+
+func (o *Options) mysqlDSN() string {
 	return fmt.Sprintf(`%s:%s@tcp(%s)/%s?charset=utf8&parseTime=%t&loc=%s`,
 		o.Username,
 		o.Password,
@@ -30,27 +45,9 @@ func (o *MySQLOptions) DSN() string {
 
 // NewMySQL create a new gorm db instance with the given options.
 func NewMySQL(opts *MySQLOptions) (*gorm.DB, error) {
-	db, err := gorm.Open(mysql.Open(opts.DSN()), &gorm.Config{
-		Logger:                                   logger.Default.LogMode(logger.Error),
-		DisableForeignKeyConstraintWhenMigrating: true,
-	})
-	if err != nil {
-		return nil, err
+	if opts == nil {
+		return nil, ErrNoConfig
 	}
 
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
-	}
-
-	// SetMaxOpenConns sets the maximum number of open connections to the database.
-	sqlDB.SetMaxOpenConns(100)
-
-	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
-	sqlDB.SetConnMaxLifetime(10)
-
-	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
-	sqlDB.SetMaxIdleConns(100)
-
-	return db, nil
+	return NewDB(opts.Options())
 }
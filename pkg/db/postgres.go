@@ -0,0 +1,43 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+
+	"gorm.io/gorm"
+)
+
+// DefaultPostgresSSLMode is used when no sslMode is configured.
+const DefaultPostgresSSLMode = "disable"
+
+func (o *Options) postgresDSN() string {
+	sslMode := o.SSLMode
+	if sslMode == "" {
+		sslMode = DefaultPostgresSSLMode
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(o.Username, o.Password),
+		Host:     o.Host,
+		Path:     "/" + o.Database,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+
+	return dsn.String()
+}
+
+// ResetSequence moves the sequence of the serial or identity column of table past its largest value on
+// PostgreSQL, so inserting rows after rows inserted with explicit ids doesn't collide with them. Other
+// drivers derive auto increment values from the rows, as do columns without sequence.
+func ResetSequence(db *gorm.DB, table, column string) error {
+	if db.Dialector.Name() != DriverPostgres {
+		return nil
+	}
+
+	quoted := db.Statement.Quote(column)
+	query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(%s), 1), MAX(%s) IS NOT NULL) FROM %s",
+		quoted, quoted, db.Statement.Quote(table))
+
+	return db.Exec(query, db.Statement.Quote(table), column).Error
+}
@@ -0,0 +1,20 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func (o *Options) sqliteDSN() string {
+	return o.Database
+}
+
+// ensureSQLiteDir creates the parent directory of the sqlite database file.
+func ensureSQLiteDir(path string) error {
+	dir := filepath.Dir(path)
+	if dir == "." || path == ":memory:" {
+		return nil
+	}
+
+	return os.MkdirAll(dir, 0755)
+}
@@ -0,0 +1,85 @@
+package fake
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// createBatchSize is the number of rows inserted per statement by Create.
+const createBatchSize = 100
+
+// Factory makes models of type T with fake values, e.g. in seeders:
+//
+//	users, err := factory.UserFactory(db).Count(10).Create()
+type Factory[T any] struct {
+	db     *gorm.DB
+	define func(f *Faker) T
+	count  int
+	seed   int64
+	states []func(m *T)
+}
+
+// New returns a factory making one model with define. The values are random unless a seed is set by Seed.
+func New[T any](db *gorm.DB, define func(f *Faker) T) *Factory[T] {
+	return &Factory[T]{
+		db:     db,
+		define: define,
+		count:  1,
+		seed:   time.Now().UnixNano(),
+	}
+}
+
+// Count sets the number of models to make.
+func (f *Factory[T]) Count(n int) *Factory[T] {
+	f.count = n
+
+	return f
+}
+
+// Seed sets the seed of the fake values, the same seed makes the same models.
+func (f *Factory[T]) Seed(seed int64) *Factory[T] {
+	f.seed = seed
+
+	return f
+}
+
+// State adds a function applied to each model after it's defined, e.g. to set a foreign key.
+func (f *Factory[T]) State(state func(m *T)) *Factory[T] {
+	f.states = append(f.states, state)
+
+	return f
+}
+
+// Make returns the models without saving them.
+func (f *Factory[T]) Make() []T {
+	faker := NewFaker(f.seed)
+
+	models := make([]T, 0, f.count)
+	for i := 0; i < f.count; i++ {
+		faker.Index = i
+
+		m := f.define(faker)
+		for _, state := range f.states {
+			state(&m)
+		}
+
+		models = append(models, m)
+	}
+
+	return models
+}
+
+// Create makes the models and inserts them into the database.
+func (f *Factory[T]) Create() ([]T, error) {
+	models := f.Make()
+	if len(models) == 0 {
+		return models, nil
+	}
+
+	if err := f.db.CreateInBatches(&models, createBatchSize).Error; err != nil {
+		return nil, err
+	}
+
+	return models, nil
+}
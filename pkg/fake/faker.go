@@ -0,0 +1,202 @@
+package fake
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var (
+	firstNames = []string{
+		"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "William", "Elizabeth",
+		"David", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen",
+		"Daniel", "Nancy", "Matthew", "Lisa", "Anthony", "Betty", "Mark", "Sandra", "Steven", "Ashley",
+	}
+	lastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+		"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+		"Lee", "Perez", "Thompson", "White", "Harris", "Sanchez", "Clark", "Ramirez", "Lewis", "Robinson",
+	}
+	words = []string{
+		"alias", "consequatur", "aut", "perferendis", "sit", "voluptatem", "accusantium", "doloremque",
+		"aperiam", "eaque", "ipsa", "quae", "ab", "illo", "inventore", "veritatis", "et", "quasi", "architecto",
+		"beatae", "vitae", "dicta", "sunt", "explicabo", "aspernatur", "odit", "fugit", "sed", "quia",
+		"consequuntur", "magni", "dolores", "eos", "qui", "ratione", "sequi", "nesciunt", "neque", "dolorem",
+		"ipsum", "dolor", "amet", "adipisci", "velit", "numquam", "eius", "modi", "tempora", "incidunt",
+	}
+	domains   = []string{"example.com", "example.org", "example.net"}
+	streets   = []string{"Main St", "Oak Ave", "Pine Rd", "Maple Dr", "Cedar Ln", "Elm St", "Lake View", "Park Ave"}
+	cities    = []string{"Springfield", "Riverside", "Franklin", "Greenville", "Bristol", "Clinton", "Fairview", "Salem"}
+	countries = []string{"United States", "United Kingdom", "Canada", "Australia", "Germany", "France", "Japan", "China"}
+)
+
+// Faker generates fake values from a seeded source, the same seed generates the same values.
+type Faker struct {
+	*rand.Rand
+
+	// Index is the index of the model being made, starting at 0.
+	Index int
+}
+
+// NewFaker returns a Faker seeded with seed.
+func NewFaker(seed int64) *Faker {
+	return &Faker{Rand: rand.New(rand.NewSource(seed))}
+}
+
+// Pick returns one of items.
+func (f *Faker) Pick(items ...string) string {
+	return items[f.Intn(len(items))]
+}
+
+// FirstName returns a first name like "Mary".
+func (f *Faker) FirstName() string {
+	return f.Pick(firstNames...)
+}
+
+// LastName returns a last name like "Smith".
+func (f *Faker) LastName() string {
+	return f.Pick(lastNames...)
+}
+
+// Name returns a full name like "Mary Smith".
+func (f *Faker) Name() string {
+	return f.FirstName() + " " + f.LastName()
+}
+
+// Username returns a username like "mary.smith12", unique for each Index.
+func (f *Faker) Username() string {
+	return fmt.Sprintf("%s.%s%d", strings.ToLower(f.FirstName()), strings.ToLower(f.LastName()), f.Index+1)
+}
+
+// Email returns an email address like "mary.smith12@example.com", unique for each Index.
+func (f *Faker) Email() string {
+	return f.Username() + "@" + f.Pick(domains...)
+}
+
+// Phone returns a phone number like "+1-555-0134-2718".
+func (f *Faker) Phone() string {
+	return fmt.Sprintf("+1-555-%04d-%04d", f.Intn(10000), f.Intn(10000))
+}
+
+// Password returns a random password of 12 letters and digits.
+func (f *Faker) Password() string {
+	const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	b := make([]byte, 12)
+	for i := range b {
+		b[i] = chars[f.Intn(len(chars))]
+	}
+
+	return string(b)
+}
+
+// Word returns a lorem ipsum word.
+func (f *Faker) Word() string {
+	return f.Pick(words...)
+}
+
+// Words returns n lorem ipsum words separated by spaces.
+func (f *Faker) Words(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = f.Word()
+	}
+
+	return strings.Join(items, " ")
+}
+
+// Title returns a title of 3 to 6 capitalized words.
+func (f *Faker) Title() string {
+	items := strings.Fields(f.Words(3 + f.Intn(4)))
+	for i, item := range items {
+		items[i] = strings.ToUpper(item[:1]) + item[1:]
+	}
+
+	return strings.Join(items, " ")
+}
+
+// Sentence returns a sentence of 6 to 12 words.
+func (f *Faker) Sentence() string {
+	sentence := f.Words(6 + f.Intn(7))
+
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+// Paragraph returns a paragraph of 3 to 5 sentences.
+func (f *Faker) Paragraph() string {
+	items := make([]string, 3+f.Intn(3))
+	for i := range items {
+		items[i] = f.Sentence()
+	}
+
+	return strings.Join(items, " ")
+}
+
+// Slug returns a slug like "ipsum-dolor-amet-12", unique for each Index.
+func (f *Faker) Slug() string {
+	return fmt.Sprintf("%s-%d", strings.ReplaceAll(f.Words(3), " ", "-"), f.Index+1)
+}
+
+// URL returns a URL like "https://example.com/ipsum-dolor".
+func (f *Faker) URL() string {
+	return fmt.Sprintf("https://%s/%s-%s", f.Pick(domains...), f.Word(), f.Word())
+}
+
+// Address returns a street address like "42 Oak Ave".
+func (f *Faker) Address() string {
+	return fmt.Sprintf("%d %s", 1+f.Intn(9999), f.Pick(streets...))
+}
+
+// City returns a city name.
+func (f *Faker) City() string {
+	return f.Pick(cities...)
+}
+
+// Country returns a country name.
+func (f *Faker) Country() string {
+	return f.Pick(countries...)
+}
+
+// IPv4 returns an IPv4 address.
+func (f *Faker) IPv4() string {
+	return fmt.Sprintf("%d.%d.%d.%d", 1+f.Intn(254), f.Intn(256), f.Intn(256), 1+f.Intn(254))
+}
+
+// UUID returns a version 4 UUID.
+func (f *Faker) UUID() string {
+	b := make([]byte, 16)
+	_, _ = f.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Int returns an int in [min, max].
+func (f *Faker) Int(min, max int) int {
+	return min + f.Intn(max-min+1)
+}
+
+// Float returns a float64 in [min, max) rounded to 2 decimals.
+func (f *Faker) Float(min, max float64) float64 {
+	return float64(int((min+f.Float64()*(max-min))*100)) / 100
+}
+
+// Bool returns true or false.
+func (f *Faker) Bool() bool {
+	return f.Intn(2) == 1
+}
+
+// Time returns a time between 2020-01-01 and 2025-01-01 UTC, to the second.
+func (f *Faker) Time() time.Time {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return start.Add(time.Duration(f.Int63n(int64(end.Sub(start)/time.Second))) * time.Second)
+}
+
+// Ptr returns a pointer to v, e.g. for nullable fields.
+func Ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mgutz/ansi"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+// migrationFileReg matches migration files generated with the TimeStr prefix, e.g. 2024_01_02_150405_create_posts_table.go,
+// including SQL migrations such as 2024_01_02_150405_create_posts_view.up.sql.
+var migrationFileReg = regexp.MustCompile(`^\d{4}_\d{2}_\d{2}_\d{6}_(.+?)(\.go|\.up\.sql|\.down\.sql)$`)
+
+// DestroyCode reverses GenerateCode: it removes the registry entries added by Register and RegisterRoutes,
+// then deletes the generated files. Files edited since they were generated are deleted only if confirmed.
+func (o *Options) DestroyCode(tmpl, path string) error {
+	if err := o.prepare(tmpl, path); err != nil {
+		return err
+	}
+
+	files, err := o.GeneratedFiles()
+	if err != nil {
+		return err
+	}
+	if err := confirmRemoveFiles(files); err != nil {
+		return err
+	}
+
+	switch Tmpl(o.Name) {
+	case TmplStore:
+		err = o.Unregister(config.Cfg.Registries.Store, o.InterfaceTemplate, o.RegisterTemplate, o.RootPackage+"/"+o.StorePath+o.RelativePath)
+	case TmplBiz:
+		err = o.Unregister(config.Cfg.Registries.Biz, o.InterfaceTemplate, o.RegisterTemplate, o.RootPackage+"/"+o.BizPath+o.RelativePath)
+	case TmplHandler:
+		err = o.UnregisterRoutes(config.Cfg.Registries.Router, o.RegisterTemplate)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 && !cmdutil.DryRun {
+		fmt.Printf("%s %s\n", ansi.Color("Not found:", "yellow"), o.FilePath)
+	}
+
+	for _, file := range files {
+		if err := cmdutil.RemoveFile(file); err != nil {
+			return err
+		}
+
+		if !cmdutil.DryRun {
+			fmt.Printf("%s %s\n", ansi.Color("Deleted:", "red"), file)
+		}
+	}
+
+	if err := forgetGeneratedFiles(files...); err != nil {
+		return err
+	}
+
+	if cmdutil.DryRun {
+		return nil
+	}
+
+	// Keep the layer directory, only the subdirectories of the path are removed.
+	dir := filepath.Clean(o.Directory)
+	return removeEmptyDirs(dir, filepath.Clean(strings.TrimSuffix(dir, o.RelativePath)))
+}
+
+// removeEmptyDirs removes dir and its parents below root as long as nothing else is left in them.
+func removeEmptyDirs(dir, root string) error {
+	for strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return nil
+		}
+
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+
+		dir = filepath.Dir(dir)
+	}
+
+	return nil
+}
+
+// GeneratedFiles returns the existing files GenerateCode generates for the current code attributes,
+// including tests generated with --with-tests and migrations of any time.
+func (o *Options) GeneratedFiles() ([]string, error) {
+	if o.Name == string(TmplMigration) {
+		return o.migrationFiles()
+	}
+
+	var files []string
+	for _, file := range []string{o.FilePath, o.TestFilePath()} {
+		if cmdutil.Exists(file) {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func (o *Options) migrationFiles() ([]string, error) {
+	entries, err := os.ReadDir(o.Directory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		matches := migrationFileReg.FindStringSubmatch(entry.Name())
+		if !entry.IsDir() && len(matches) == 3 && matches[1] == o.VariableNameSnake {
+			files = append(files, filepath.Join(o.Directory, entry.Name()))
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,132 @@
+// ABOUTME: Tests for destroying generated code.
+// ABOUTME: Verifies generated files, tests and migrations are deleted with empty subdirectories, edited files once confirmed.
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+func TestDestroyCode(t *testing.T) {
+	setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	o := &Options{FieldSpec: "title:string", WithTests: true}
+	if err := o.GenerateCode(string(TmplStore), "post"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	keep := filepath.Join(config.Cfg.Directory.Store, "comment.go")
+	if err := os.WriteFile(keep, []byte("package store\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	o = &Options{}
+	if err := o.DestroyCode(string(TmplStore), "post"); err != nil {
+		t.Fatalf("DestroyCode failed: %v", err)
+	}
+
+	for _, file := range []string{"post.go", "post_test.go"} {
+		if _, err := os.Stat(filepath.Join(config.Cfg.Directory.Store, file)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s deleted", file)
+		}
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("Expected other files kept: %v", err)
+	}
+
+	// Empty directories below the layer directory are removed
+	if err := (&Options{}).GenerateCode(string(TmplModel), "admin/post"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if err := (&Options{}).DestroyCode(string(TmplModel), "admin/post"); err != nil {
+		t.Fatalf("DestroyCode failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(config.Cfg.Directory.Model, "admin")); !os.IsNotExist(err) {
+		t.Error("Expected empty admin directory removed")
+	}
+	if _, err := os.Stat(config.Cfg.Directory.Model); err != nil {
+		t.Errorf("Expected empty model directory kept: %v", err)
+	}
+}
+
+func TestDestroyCode_Modified(t *testing.T) {
+	setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	o := &Options{}
+	if err := o.GenerateCode(string(TmplModel), "post"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if err := os.WriteFile(o.FilePath, []byte("package model\n\n// Edited\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+
+	// Refuse to delete the edited file.
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	r, w, _ := os.Pipe()
+	w.WriteString("n\n")
+	w.Close()
+	os.Stdin = r
+
+	if err := (&Options{}).DestroyCode(string(TmplModel), "post"); err == nil {
+		t.Fatal("Expected error when deleting the edited file is refused")
+	}
+	if _, err := os.Stat(o.FilePath); err != nil {
+		t.Errorf("Expected the edited file kept: %v", err)
+	}
+
+	cmdutil.Overwrite = true
+	defer func() { cmdutil.Overwrite = false }()
+	if err := (&Options{}).DestroyCode(string(TmplModel), "post"); err != nil {
+		t.Fatalf("DestroyCode failed: %v", err)
+	}
+	if _, err := os.Stat(o.FilePath); !os.IsNotExist(err) {
+		t.Error("Expected the edited file deleted with --force")
+	}
+}
+
+func TestGeneratedFiles_Migration(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"2024_01_02_150405_create_posts_table.go",
+		"2024_02_03_150405_create_posts_table.go",
+		"2024_03_04_150405_create_posts_table.down.sql",
+		"2024_03_04_150405_create_posts_table.up.sql",
+		"2024_01_02_150405_alter_create_posts_table.go",
+		"2024_01_02_150405_create_posts_table.sql",
+		"create_posts_table.go",
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("package migration\n"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	o := &Options{Name: string(TmplMigration), Directory: dir, VariableNameSnake: "create_posts_table"}
+	got, err := o.GeneratedFiles()
+	if err != nil {
+		t.Fatalf("GeneratedFiles failed: %v", err)
+	}
+
+	var names []string
+	for _, file := range got {
+		names = append(names, filepath.Base(file))
+	}
+	if strings.Join(names, ",") != strings.Join(files[:4], ",") {
+		t.Errorf("Expected the Go and SQL create_posts_table migrations, got %v", names)
+	}
+}
@@ -0,0 +1,275 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FactoryField is a field of a model assigned a fake value by a generated factory.
+type FactoryField struct {
+	Name  string
+	Value string // Go expression of the fake value, e.g. f.Email()
+}
+
+// factoryColumn is a column of the model a factory is generated for.
+type factoryColumn struct {
+	Name   string
+	Column string
+	Type   string
+	Basic  string // Underlying Go type, empty if unknown
+}
+
+// GetFactoryFields sets the fake values of the factory from the --fields spec, the db table of --table
+// or the model struct parsed from the model directory, in this order.
+func (o *Options) GetFactoryFields() error {
+	var columns []factoryColumn
+	switch {
+	case o.FieldSpec != "" || o.Table != "":
+		if o.FieldSpec != "" {
+			if err := o.GetFieldsFromSpec(); err != nil {
+				return err
+			}
+		} else if err := o.ReadMetaFields(); err != nil {
+			return err
+		}
+
+		for _, field := range o.MetaFields {
+			columns = append(columns, factoryColumn{
+				Name:   field.Name,
+				Column: field.ColumnName,
+				Type:   field.Type,
+				Basic:  fieldBasic(field.Type),
+			})
+		}
+		o.FactoryModel = o.ModelName + "M"
+	default:
+		model, err := o.findModel()
+		if err != nil {
+			return err
+		}
+
+		for _, field := range model.Fields {
+			columns = append(columns, factoryColumn{Name: field.Name, Column: field.Column, Type: field.Type, Basic: field.Basic})
+		}
+		o.FactoryModel = model.Name
+	}
+
+	o.FactoryFields = nil
+	for _, column := range columns {
+		if value := fakeValue(column); value != "" {
+			o.FactoryFields = append(o.FactoryFields, &FactoryField{Name: column.Name, Value: value})
+		}
+	}
+
+	return nil
+}
+
+// findModel parses the model package of the factory and returns the model named ModelName with or
+// without the M suffix.
+func (o *Options) findModel() (*modelStruct, error) {
+	dir := o.ModelPath + o.RelativePath
+	pkg, err := parseModelPackage(dir, path.Join(o.RootPackage, filepath.ToSlash(dir)))
+	if err != nil {
+		return nil, err
+	}
+
+	if pkg != nil {
+		for _, model := range pkg.models() {
+			if model.Name != o.ModelName+"M" && model.Name != o.ModelName {
+				continue
+			}
+
+			// The template imports the model package as model.
+			for _, field := range model.Fields {
+				field.Type = strings.Replace(field.Type, pkg.name+".", "model.", 1)
+			}
+
+			return model, nil
+		}
+	}
+
+	return nil, fmt.Errorf("model %sM not found in %s, use --table or --fields to read the fields", o.ModelName, dir)
+}
+
+// fieldBasic returns the underlying Go type of a field type read from db or --fields.
+func fieldBasic(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	if basic, ok := basicType(typ); ok {
+		return basic
+	}
+
+	switch typ {
+	case "time.Time", "gorm.DeletedAt":
+		return "time.Time"
+	case "[]byte":
+		return "[]byte"
+	}
+
+	return ""
+}
+
+// fakeValue returns the Go expression of a fake value of column, chosen by the column name and Go
+// type. It returns an empty string for columns the database or the seeder sets, i.e. the primary
+// key, foreign keys, updated_at and deleted_at, and for types it can't fake.
+func fakeValue(column factoryColumn) string {
+	name := column.Column
+	if name == "id" || name == "updated_at" || name == "deleted_at" || strings.HasSuffix(name, "_id") {
+		return ""
+	}
+
+	typ := strings.TrimPrefix(column.Type, "*")
+	if strings.HasPrefix(typ, "sql.Null") {
+		return ""
+	}
+
+	var value, valueType string
+	switch basic := column.Basic; {
+	case basic == "string":
+		value, valueType = fakeString(name), "string"
+	case basic == "bool":
+		value, valueType = "f.Bool()", "bool"
+	case basic == "time.Time":
+		value, valueType = "f.Time()", "time.Time"
+	case basic == "[]byte":
+		value, valueType = "[]byte(f.Sentence())", "[]byte"
+	case strings.HasPrefix(basic, "float"):
+		value, valueType = "f.Float(0, 1000)", "float64"
+	case strings.HasPrefix(basic, "int") || strings.HasPrefix(basic, "uint"):
+		value, valueType = fakeInt(name, basic), "int"
+	default:
+		return ""
+	}
+
+	// Convert to named types, e.g. model.Status(f.Int(0, 1)).
+	if typ != valueType {
+		value = typ + "(" + value + ")"
+	}
+	if strings.HasPrefix(column.Type, "*") {
+		value = "fake.Ptr(" + value + ")"
+	}
+
+	return value
+}
+
+// GetStoreTestFields sets the values the generated store test creates the model with, so the row
+// satisfies the not null and unique columns read from --fields or --table.
+func (o *Options) GetStoreTestFields() {
+	o.StoreTestFields = nil
+	for _, field := range o.MetaFields {
+		column := factoryColumn{Name: field.Name, Column: field.ColumnName, Type: field.Type, Basic: fieldBasic(field.Type)}
+		if value := testValue(column); value != "" {
+			o.StoreTestFields = append(o.StoreTestFields, &FactoryField{Name: column.Name, Value: value})
+		}
+	}
+}
+
+// StoreTestFieldsUse returns true if a value of StoreTestFields uses package pkg, e.g. time.
+func (o *Options) StoreTestFieldsUse(pkg string) bool {
+	for _, field := range o.StoreTestFields {
+		if strings.Contains(field.Value, pkg+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// testValue returns the Go expression of a constant value of column, empty for the columns left to
+// the database, nullable columns and types it can't fill.
+func testValue(column factoryColumn) string {
+	name := column.Column
+	if name == "id" || name == "created_at" || name == "updated_at" || name == "deleted_at" ||
+		strings.HasPrefix(column.Type, "*") {
+		return ""
+	}
+
+	var value, valueType string
+	switch basic := column.Basic; {
+	case basic == "string":
+		value, valueType = strconv.Quote(name), "string"
+	case basic == "bool":
+		value, valueType = "true", "bool"
+	case basic == "time.Time":
+		value, valueType = "time.Now()", "time.Time"
+	case basic == "[]byte":
+		value, valueType = "[]byte("+strconv.Quote(name)+")", "[]byte"
+	case strings.HasPrefix(basic, "float"), strings.HasPrefix(basic, "int"), strings.HasPrefix(basic, "uint"):
+		value, valueType = "1", column.Type
+	default:
+		return ""
+	}
+
+	// Convert to named types, e.g. model.Status("active").
+	if column.Type != valueType {
+		value = column.Type + "(" + value + ")"
+	}
+
+	return value
+}
+
+// fakeString returns the fake value of a string column by its name.
+func fakeString(column string) string {
+	has := func(words ...string) bool {
+		for _, word := range words {
+			if strings.Contains(column, word) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch {
+	case has("email"):
+		return "f.Email()"
+	case column == "first_name":
+		return "f.FirstName()"
+	case column == "last_name":
+		return "f.LastName()"
+	case has("username", "user_name", "nickname", "nick_name"):
+		return "f.Username()"
+	case column == "name" || strings.HasSuffix(column, "_name"):
+		return "f.Name()"
+	case has("phone", "mobile"):
+		return "f.Phone()"
+	case has("password"):
+		return "f.Password()"
+	case has("url", "website", "link", "avatar", "image"):
+		return "f.URL()"
+	case column == "title" || column == "subject" || strings.HasSuffix(column, "_title"):
+		return "f.Title()"
+	case has("description", "content", "body", "summary", "bio", "remark", "note", "comment"):
+		return "f.Paragraph()"
+	case has("address"):
+		return "f.Address()"
+	case has("city"):
+		return "f.City()"
+	case has("country"):
+		return "f.Country()"
+	case column == "ip" || strings.HasSuffix(column, "_ip"):
+		return "f.IPv4()"
+	case has("uuid"):
+		return "f.UUID()"
+	case has("slug"):
+		return "f.Slug()"
+	}
+
+	return "f.Word()"
+}
+
+// fakeInt returns the fake value of an integer column by its name.
+func fakeInt(column, basic string) string {
+	switch {
+	case column == "age":
+		return "f.Int(18, 80)"
+	case strings.Contains(column, "status") || strings.Contains(column, "state") || column == "type":
+		return "f.Int(0, 1)"
+	case basic == "int8" || basic == "uint8":
+		return "f.Int(0, 100)"
+	}
+
+	return "f.Int(1, 1000)"
+}
@@ -0,0 +1,82 @@
+// ABOUTME: Tests for generating model factories with fake values.
+// ABOUTME: Verifies fields are read from the model struct or --fields and faked by column name and type.
+package generator
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFactory_FromModel(t *testing.T) {
+	setupDiffProject(t)
+
+	o := &Options{}
+	if err := o.GenerateCode(string(TmplFactory), "post"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	content, err := os.ReadFile(o.FilePath)
+	if err != nil {
+		t.Fatalf("Failed to read factory: %v", err)
+	}
+	code := string(content)
+
+	for _, want := range []string{
+		`model "example.com/app/internal/model"`,
+		"func PostFactory(db *gorm.DB) *fake.Factory[model.PostM] {",
+		"m.CreatedAt = f.Time()",
+		"m.Title = f.Title()",
+		"m.Slug = f.Slug()",
+		"m.Status = model.Status(f.Int(0, 1))",
+		"m.PublishedAt = fake.Ptr(f.Time())",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected factory to contain %q, got:\n%s", want, code)
+		}
+	}
+
+	// The database and the seeder set keys, updated_at and deleted_at.
+	for _, unwanted := range []string{"m.ID", "m.UpdatedAt", "m.DeletedAt", "m.AuthorID", "m.Author "} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("Expected factory not to contain %q", unwanted)
+		}
+	}
+}
+
+func TestGenerateFactory_FromFieldSpec(t *testing.T) {
+	setupDiffProject(t)
+
+	o := &Options{FieldSpec: "email:string:unique,nickname:string,phone:string:nullable,age:uint8,score:float,active:bool"}
+	if err := o.GenerateCode(string(TmplFactory), "account"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	content, err := os.ReadFile(o.FilePath)
+	if err != nil {
+		t.Fatalf("Failed to read factory: %v", err)
+	}
+
+	for _, want := range []string{
+		"*fake.Factory[model.AccountM]",
+		"m.Email = f.Email()",
+		"m.Nickname = f.Username()",
+		"m.Phone = fake.Ptr(f.Phone())",
+		"m.Age = uint8(f.Int(18, 80))",
+		"m.Score = f.Float(0, 1000)",
+		"m.Active = f.Bool()",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected factory to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerateFactory_ModelNotFound(t *testing.T) {
+	setupDiffProject(t)
+
+	err := (&Options{}).GenerateCode(string(TmplFactory), "missing")
+	if err == nil || !strings.Contains(err.Error(), "MissingM not found") {
+		t.Fatalf("GenerateCode error = %v, want model not found", err)
+	}
+}
@@ -65,6 +65,25 @@ func (o *Options) GetFieldsFromDB() error {
 		return err
 	}
 
+	return o.BuildFields()
+}
+
+// GetFieldsFromSpec reads fields from the --fields spec instead of db table.
+func (o *Options) GetFieldsFromSpec() error {
+	if len(o.MetaFields) == 0 {
+		fields, err := ParseFieldSpec(o.FieldSpec)
+		if err != nil {
+			return err
+		}
+
+		o.MetaFields = fields
+	}
+
+	return o.BuildFields()
+}
+
+// BuildFields renders MetaFields into Fields, MainFields and UpdatableFields with the field template.
+func (o *Options) BuildFields() error {
 	o.Fields = ""
 	o.MainFields = ""
 	o.UpdatableFields = ""
@@ -81,7 +100,10 @@ func (o *Options) GetFieldsFromDB() error {
 			field.Type = "*time.Time"
 		}
 
-		field.GORMTag.Remove("column")
+		// Keep column tag if it's the only one, avoid an empty gorm tag.
+		if len(field.GORMTag) > 1 {
+			field.GORMTag.Remove("column")
+		}
 
 		// Replaces
 		replaces := make(map[string]string)
@@ -91,6 +113,12 @@ func (o *Options) GetFieldsFromDB() error {
 		replaces["{{.JsonTag}}"] = strcase.ToLowerCamel(field.Tag["json"])
 		replaces["{{.Comment}}"] = comment
 
+		// Nullable fields are pointers already, assign them without dereference.
+		replaces["{{.Deref}}"] = "*"
+		if strings.HasPrefix(field.Type, "*") {
+			replaces["{{.Deref}}"] = ""
+		}
+
 		// Replace
 		fieldTemplate := o.FieldTemplate
 		for search, replace := range replaces {
@@ -129,3 +157,8 @@ func (o *Options) GetFieldsFromDB() error {
 
 	return nil
 }
+
+// UsesTime reports whether MainFields refer to the time package, for the imports of the model template.
+func (o *Options) UsesTime() bool {
+	return strings.Contains(o.MainFields, "time.")
+}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	genField "gorm.io/gen/field"
+	"gorm.io/gorm/schema"
+)
+
+// fieldSpecType maps a field spec type to go type and gorm column type.
+type fieldSpecType struct {
+	GoType     string
+	ColumnType string
+}
+
+var fieldSpecTypes = map[string]fieldSpecType{
+	"string":    {GoType: "string", ColumnType: "varchar(255)"},
+	"text":      {GoType: "string", ColumnType: "text"},
+	"int":       {GoType: "int"},
+	"int8":      {GoType: "int8"},
+	"int16":     {GoType: "int16"},
+	"int32":     {GoType: "int32"},
+	"int64":     {GoType: "int64"},
+	"bigint":    {GoType: "int64"},
+	"uint":      {GoType: "uint"},
+	"uint8":     {GoType: "uint8"},
+	"uint16":    {GoType: "uint16"},
+	"uint32":    {GoType: "uint32"},
+	"uint64":    {GoType: "uint64"},
+	"float":     {GoType: "float64"},
+	"float32":   {GoType: "float32"},
+	"float64":   {GoType: "float64"},
+	"decimal":   {GoType: "float64", ColumnType: "decimal(20,6)"},
+	"bool":      {GoType: "bool"},
+	"time":      {GoType: "time.Time"},
+	"datetime":  {GoType: "time.Time"},
+	"timestamp": {GoType: "time.Time"},
+	"date":      {GoType: "time.Time", ColumnType: "date"},
+}
+
+// gormModelFields are the fields of gorm.Model, the same as a table created by a gorm.Model migration.
+var gormModelFields = []struct {
+	Name   string
+	Column string
+	Type   string
+}{
+	{Name: "ID", Column: "id", Type: "uint"},
+	{Name: "CreatedAt", Column: "created_at", Type: "time.Time"},
+	{Name: "UpdatedAt", Column: "updated_at", Type: "time.Time"},
+	{Name: "DeletedAt", Column: "deleted_at", Type: "gorm.DeletedAt"},
+}
+
+// ParseFieldSpec parses field definitions like "name:string:unique,age:int:nullable"
+// into the same field list ReadMetaFields reads from db.
+//
+// Each definition is name:type[:modifier...], supported modifiers are
+// unique, index, nullable, default=VALUE and comment=TEXT. Values containing
+// ',' or ':' are quoted, e.g. comment='Price, in cents'.
+func ParseFieldSpec(spec string) ([]*Field, error) {
+	var fields []*Field
+	for _, item := range gormModelFields {
+		field := &Field{
+			Name:       item.Name,
+			Type:       item.Type,
+			ColumnName: item.Column,
+			Tag:        genField.Tag{genField.TagKeyJson: item.Column},
+			GORMTag:    genField.GormTag{genField.TagKeyGormColumn: {item.Column}},
+		}
+		if item.Name == "ID" {
+			field.GORMTag.Set(genField.TagKeyGormPrimaryKey)
+		}
+
+		fields = append(fields, field)
+	}
+
+	definitions, err := splitQuoted(spec, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, definition := range definitions {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+
+		field, err := parseFieldDefinition(definition)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[field.ColumnName] {
+			return nil, fmt.Errorf("duplicate field %q in --fields", field.ColumnName)
+		}
+		seen[field.ColumnName] = true
+
+		fields = append(fields, field)
+	}
+
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("no field defined in --fields %q", spec)
+	}
+
+	return fields, nil
+}
+
+func parseFieldDefinition(definition string) (*Field, error) {
+	parts, err := splitQuoted(definition, ':')
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) < 2 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid field definition %q, expected name:type[:modifier...]", definition)
+	}
+
+	column := strcase.ToSnake(parts[0])
+	for _, item := range gormModelFields {
+		if item.Column == column {
+			return nil, fmt.Errorf("field %q is already defined by gorm.Model", column)
+		}
+	}
+
+	typ, ok := fieldSpecTypes[strings.ToLower(parts[1])]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %q for field %q", parts[1], column)
+	}
+
+	field := &Field{
+		Name:       schema.NamingStrategy{}.SchemaName(column),
+		Type:       typ.GoType,
+		ColumnName: column,
+		Tag:        genField.Tag{genField.TagKeyJson: column},
+		GORMTag:    genField.GormTag{genField.TagKeyGormColumn: {column}},
+	}
+	if typ.ColumnType != "" {
+		field.GORMTag.Set(genField.TagKeyGormType, typ.ColumnType)
+	}
+
+	nullable := false
+	for _, modifier := range parts[2:] {
+		key, value, _ := strings.Cut(modifier, "=")
+		value = unquote(value)
+		if strings.Contains(value, ";") {
+			return nil, fmt.Errorf("value of %s for field %q can't contain ';', it separates gorm tag settings", key, column)
+		}
+		switch key {
+		case "nullable":
+			nullable = true
+		case "unique":
+			field.GORMTag.Set(genField.TagKeyGormUniqueIndex)
+		case "index":
+			field.GORMTag.Set(genField.TagKeyGormIndex)
+		case "default":
+			field.GORMTag.Set(genField.TagKeyGormDefault, value)
+		case "comment":
+			field.ColumnComment = value
+			field.GORMTag.Set(genField.TagKeyGormComment, value)
+		default:
+			return nil, fmt.Errorf("unsupported modifier %q for field %q", modifier, column)
+		}
+	}
+
+	// Same as gen with FieldNullable, nullable columns are pointers.
+	if nullable {
+		field.Type = "*" + field.Type
+	} else {
+		field.GORMTag.Set(genField.TagKeyGormNotNull)
+	}
+
+	return field, nil
+}
+
+// splitQuoted splits s on sep outside of single or double quotes, keeping the quotes.
+func splitQuoted(s string, sep rune) ([]string, error) {
+	var (
+		parts   []string
+		current strings.Builder
+		quote   rune
+	)
+
+	for _, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == sep:
+			parts = append(parts, current.String())
+			current.Reset()
+
+			continue
+		}
+
+		current.WriteRune(c)
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in field definition %q", s)
+	}
+
+	return append(parts, current.String()), nil
+}
+
+// unquote removes the single or double quotes around value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
@@ -0,0 +1,114 @@
+// ABOUTME: Tests for inline field definitions used by make commands without db.
+// ABOUTME: Verifies spec parsing and rendering through the field templates.
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFieldSpec(t *testing.T) {
+	fields, err := ParseFieldSpec("name:string:unique,age:int:nullable,email:string:index,user_id:uint:comment=Owner")
+	if err != nil {
+		t.Fatalf("ParseFieldSpec failed: %v", err)
+	}
+
+	// gorm.Model fields come first, same as a table read from db.
+	if len(fields) != 8 {
+		t.Fatalf("Expected 8 fields, got %d", len(fields))
+	}
+	if fields[0].Name != "ID" || fields[3].Name != "DeletedAt" {
+		t.Errorf("Expected gorm.Model fields first, got %s and %s", fields[0].Name, fields[3].Name)
+	}
+
+	tests := []struct {
+		index   int
+		name    string
+		typ     string
+		gormTag string
+		json    string
+	}{
+		{index: 4, name: "Name", typ: "string", gormTag: "column:name;type:varchar(255);not null;uniqueIndex", json: "name"},
+		{index: 5, name: "Age", typ: "*int", gormTag: "column:age", json: "age"},
+		{index: 6, name: "Email", typ: "string", gormTag: "column:email;type:varchar(255);not null;index", json: "email"},
+		{index: 7, name: "UserID", typ: "uint", gormTag: "column:user_id;not null;comment:Owner", json: "user_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := fields[tt.index]
+			if field.Name != tt.name {
+				t.Errorf("Name = %q, want %q", field.Name, tt.name)
+			}
+			if field.Type != tt.typ {
+				t.Errorf("Type = %q, want %q", field.Type, tt.typ)
+			}
+			if tag := field.GORMTag.Build(); tag != tt.gormTag {
+				t.Errorf("GORMTag = %q, want %q", tag, tt.gormTag)
+			}
+			if field.Tag["json"] != tt.json {
+				t.Errorf("json tag = %q, want %q", field.Tag["json"], tt.json)
+			}
+		})
+	}
+
+	if fields[7].ColumnComment != "Owner" {
+		t.Errorf("ColumnComment = %q, want %q", fields[7].ColumnComment, "Owner")
+	}
+}
+
+func TestParseFieldSpec_Quoted(t *testing.T) {
+	fields, err := ParseFieldSpec(`price:int:comment='Price, in cents: 100 = 1.00',status:string:default="a,b"`)
+	if err != nil {
+		t.Fatalf("ParseFieldSpec failed: %v", err)
+	}
+	if len(fields) != 6 {
+		t.Fatalf("Expected 6 fields, got %d", len(fields))
+	}
+
+	if comment := fields[4].ColumnComment; comment != "Price, in cents: 100 = 1.00" {
+		t.Errorf("ColumnComment = %q, want the quoted comment", comment)
+	}
+	if tag := fields[5].GORMTag.Build(); tag != "column:status;type:varchar(255);not null;default:a,b" {
+		t.Errorf("GORMTag = %q, want the quoted default", tag)
+	}
+}
+
+func TestParseFieldSpec_Errors(t *testing.T) {
+	specs := []string{
+		"",
+		"name",
+		"name:unknown",
+		"name:string:primary",
+		"name:string,name:text",
+		"id:uint",
+		"name:string:comment='Unterminated",
+		"name:string:comment=a;b",
+	}
+
+	for _, spec := range specs {
+		if _, err := ParseFieldSpec(spec); err == nil {
+			t.Errorf("ParseFieldSpec(%q) expected error, got nil", spec)
+		}
+	}
+}
+
+func TestGetFieldsFromSpec(t *testing.T) {
+	o := &Options{Name: string(TmplModel), FieldSpec: "name:string:unique,age:int,bio:text:nullable"}
+	o.ReadCodeTemplates()
+
+	if err := o.GetFieldsFromSpec(); err != nil {
+		t.Fatalf("GetFieldsFromSpec failed: %v", err)
+	}
+
+	expected := "Name string `gorm:\"type:varchar(255);not null;uniqueIndex\" json:\"name\"`\n" +
+		"Age int `gorm:\"not null\" json:\"age\"`\n" +
+		"Bio *string `gorm:\"type:text\" json:\"bio\"`"
+	if o.MainFields != expected {
+		t.Errorf("MainFields = %q, want %q", o.MainFields, expected)
+	}
+
+	if !strings.Contains(o.Fields, "ID uint") {
+		t.Errorf("Fields should contain gorm.Model fields, got %q", o.Fields)
+	}
+}
@@ -2,47 +2,69 @@ package generator
 
 import (
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/bingo-project/component-base/cli/console"
 	"github.com/gertd/go-pluralize"
 	"github.com/iancoleman/strcase"
+	"github.com/mgutz/ansi"
 
 	"github.com/bingo-project/bingoctl/pkg/config"
 	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
 )
 
 func (o *Options) GenerateCode(tmpl, path string) error {
-	dir := GetMapDirectory(tmpl)
+	if err := o.prepare(tmpl, path); err != nil {
+		return err
+	}
 
-	// Apply service-based path inference if --service flag is provided and -d is not set
-	if o.Service != "" && o.Directory == "" {
-		inferredDir, err := o.InferDirectoryForService(dir, o.Service)
-		if err != nil {
-			return fmt.Errorf("failed to infer directory for service %s: %w", o.Service, err)
+	// Generate from --fields or db table.
+	dbTemplates := []Tmpl{TmplModel, TmplRequest, TmplStore, TmplBiz}
+	if slices.Contains(dbTemplates, Tmpl(o.Name)) {
+		if o.FieldSpec != "" {
+			if err := o.GetFieldsFromSpec(); err != nil {
+				return err
+			}
+		} else if o.Table != "" {
+			_ = o.GetFieldsFromDB()
 		}
-		dir = inferredDir
 	}
 
-	o.SetName(tmpl)
-	o.ReadCodeTemplates()
-	o.GenerateAttributes(dir, path)
+	if o.Name == string(TmplFactory) {
+		if err := o.GetFactoryFields(); err != nil {
+			return err
+		}
+	}
 
-	// Generate from db table.
-	dbTemplates := []Tmpl{TmplModel, TmplRequest, TmplStore, TmplBiz}
-	if slices.Contains(dbTemplates, Tmpl(o.Name)) && o.Table != "" {
-		_ = o.GetFieldsFromDB()
+	// Registered seeders and RunSeeders can't be mixed, the seeder is left to be added to RunSeeders.
+	if o.Name == string(TmplSeeder) {
+		o.SeederRegister = !declaresFunc(o.Directory, "RunSeeders")
 	}
 
-	err := cmdutil.GenerateCode(o.FilePath, o.CodeTemplate, o.Name, o)
+	err := o.generateFile(o.FilePath, o.CodeTemplate, o.Name, o.Name+".tpl", o.Name+"_field.tpl")
 	if err != nil {
 		return err
 	}
 
+	if o.WithTests && o.TestTemplate != "" {
+		if o.Name == string(TmplStore) {
+			o.GetStoreTestFields()
+		}
+
+		err = o.generateFile(o.TestFilePath(), o.TestTemplate, o.Name+"_test", o.Name+"_test.tpl")
+		if err != nil {
+			return err
+		}
+	}
+
 	if o.Name == string(TmplStore) {
 		err = o.Register(config.Cfg.Registries.Store, o.InterfaceTemplate, o.RegisterTemplate, o.RootPackage+"/"+o.StorePath+o.RelativePath)
 		if err != nil {
@@ -57,9 +79,78 @@ func (o *Options) GenerateCode(tmpl, path string) error {
 		}
 	}
 
-	// Format code
-	cmd := exec.Command("gofmt", "-w", o.FilePath)
-	_ = cmd.Run()
+	if o.Name == string(TmplHandler) {
+		err = o.RegisterRoutes(config.Cfg.Registries.Router, o.RegisterTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.Name == string(TmplSeeder) && !o.SeederRegister {
+		console.Warn(fmt.Sprintf("%s declares RunSeeders, run %s there or register the seeders it runs with seed.Register.",
+			filepath.Clean(o.Directory), o.StructName))
+	}
+
+	return nil
+}
+
+// declaresFunc returns true if the package in dir declares the top-level function name.
+func declaresFunc(dir, name string) bool {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.SkipObjectResolution)
+	if err != nil {
+		return false
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// generateFile renders codeTemplate to filePath and records it in the manifest along with templates it's read from.
+func (o *Options) generateFile(filePath, codeTemplate, name string, templates ...string) error {
+	content, err := cmdutil.RenderCode(filePath, codeTemplate, name, o)
+	if err != nil {
+		return err
+	}
+
+	err = o.writeGeneratedFile(filePath, content, true, templates...)
+	if err != nil {
+		return err
+	}
+
+	if !cmdutil.DryRun {
+		fmt.Printf("%s %s\n", ansi.Color("Generated:", "green"), filePath)
+	}
+
+	return nil
+}
+
+// prepare reads the templates of tmpl and sets the code attributes of path.
+func (o *Options) prepare(tmpl, path string) error {
+	dir := GetMapDirectory(tmpl)
+
+	// Apply service-based path inference if --service flag is provided and -d is not set
+	if o.Service != "" && o.Directory == "" {
+		inferredDir, err := o.InferDirectoryForService(dir, o.Service)
+		if err != nil {
+			return fmt.Errorf("failed to infer directory for service %s: %w", o.Service, err)
+		}
+		dir = inferredDir
+	}
+
+	o.SetName(tmpl)
+	o.ReadCodeTemplates()
+	o.GenerateAttributes(dir, path)
 
 	return nil
 }
@@ -88,11 +179,14 @@ func GetMapDirectory(tmpl string) (dir string) {
 		dir = config.Cfg.Directory.Job
 	}
 	if tmpl == string(TmplMigration) {
-		dir = config.Cfg.Directory.Migration
+		dir = config.Cfg.GetMigrationDirectory()
 	}
 	if tmpl == string(TmplSeeder) {
 		dir = config.Cfg.Directory.Seeder
 	}
+	if tmpl == string(TmplFactory) {
+		dir = config.Cfg.GetFactoryDirectory()
+	}
 
 	return
 }
@@ -3,9 +3,16 @@
 package generator
 
 import (
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"golang.org/x/tools/imports"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
 )
 
 func TestDiscoverServices(t *testing.T) {
@@ -309,3 +316,132 @@ func TestInferDirectoryForService_DiscoveryFailure(t *testing.T) {
 		t.Errorf("InferDirectoryForService with no cmd/ = %q, want %q", result, expected)
 	}
 }
+
+func TestGenerateCode_WithTests(t *testing.T) {
+	setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	tests := []struct {
+		tmpl     Tmpl
+		testFile string
+	}{
+		{tmpl: TmplStore, testFile: "internal/pkg/store/post_test.go"},
+		{tmpl: TmplBiz, testFile: "internal/apiserver/biz/post_test.go"},
+		{tmpl: TmplHandler, testFile: "internal/apiserver/handler/http/post_test.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.tmpl), func(t *testing.T) {
+			o := &Options{FieldSpec: "title:string,body:text:nullable,published_at:time", WithTests: true}
+			if err := o.GenerateCode(string(tt.tmpl), "post"); err != nil {
+				t.Fatalf("GenerateCode failed: %v", err)
+			}
+
+			// Generated tests must be valid go code with the imports they use. They aren't compiled here,
+			// as they depend on the packages of a bingo project.
+			assertImports(t, tt.testFile)
+		})
+	}
+
+	// The store test fills the fields which aren't nullable.
+	content, _ := os.ReadFile("internal/pkg/store/post_test.go")
+	for _, want := range []string{`Title:       "title",`, "PublishedAt: time.Now(),"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected the store test to contain %s, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(string(content), "Body:") {
+		t.Errorf("Expected the nullable body left unset, got:\n%s", content)
+	}
+
+	o := &Options{}
+	if err := o.GenerateCode(string(TmplModel), "comment"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if _, err := os.Stat("internal/pkg/model/comment_test.go"); !os.IsNotExist(err) {
+		t.Error("Expected no test generated without --with-tests")
+	}
+}
+
+func TestGenerateCode_Imports(t *testing.T) {
+	setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	specs := map[string]string{
+		"post":    "title:string,published_at:time:nullable",
+		"comment": "body:text",
+	}
+	for name, spec := range specs {
+		for _, tmpl := range []Tmpl{TmplModel, TmplRequest, TmplStore, TmplBiz, TmplHandler} {
+			o := &Options{FieldSpec: spec}
+			if err := o.GenerateCode(string(tmpl), name); err != nil {
+				t.Fatalf("GenerateCode %s %s failed: %v", tmpl, name, err)
+			}
+			assertImports(t, o.FilePath)
+		}
+	}
+}
+
+// assertImports fails if the go file has a syntax error, or imports goimports would add or remove.
+func assertImports(t *testing.T, file string) {
+	t.Helper()
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", file, err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), file, content, 0); err != nil {
+		t.Fatalf("Failed to parse generated %s: %v", file, err)
+	}
+
+	fixed, err := imports.Process(file, content, &imports.Options{Comments: true, TabIndent: true, TabWidth: 8})
+	if err != nil {
+		t.Fatalf("Failed to process imports of %s: %v", file, err)
+	}
+	if string(fixed) != string(content) {
+		t.Errorf("Generated %s has wrong imports, want:\n%s", file, fixed)
+	}
+}
+
+func TestGenerateCode_SeederRegister(t *testing.T) {
+	setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	o := &Options{}
+	if err := o.GenerateCode(string(TmplSeeder), "role_seeder"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	content, _ := os.ReadFile(o.FilePath)
+	if !strings.Contains(string(content), "seed.Register(RoleSeeder{})") {
+		t.Errorf("Expected the seeder registered, got:\n%s", content)
+	}
+	assertImports(t, o.FilePath)
+
+	// Seeders of packages running them by RunSeeders are left to be added there.
+	runSeeders := "package seeder\n\nfunc RunSeeders(name string) error {\n\treturn nil\n}\n"
+	if err := os.WriteFile(filepath.Join(o.Directory, "seeder.go"), []byte(runSeeders), 0644); err != nil {
+		t.Fatalf("Failed to write RunSeeders: %v", err)
+	}
+
+	o = &Options{}
+	if err := o.GenerateCode(string(TmplSeeder), "user_seeder"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	content, _ = os.ReadFile(o.FilePath)
+	if strings.Contains(string(content), "seed.Register") {
+		t.Errorf("Expected the seeder not registered next to RunSeeders, got:\n%s", content)
+	}
+	assertImports(t, o.FilePath)
+}
@@ -0,0 +1,274 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mgutz/ansi"
+
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+// ManifestPath is the file recording the code generated in the project, relative to the project root.
+const ManifestPath = ".bingo/manifest.json"
+
+// File states of a generated file.
+const (
+	FilePristine  = "pristine"  // unchanged since it was generated
+	FileModified  = "modified"  // edited since it was generated
+	FileMissing   = "missing"   // deleted since it was generated
+	FileUntracked = "untracked" // not recorded in the manifest
+)
+
+// Manifest records the files written by GenerateCode and GenerateService.
+type Manifest struct {
+	Files map[string]*ManifestEntry `json:"files"`
+}
+
+// ManifestEntry records how a file was generated.
+type ManifestEntry struct {
+	Templates    []string        `json:"templates"`
+	Options      ManifestOptions `json:"options"`
+	Hash         string          `json:"hash"`
+	TemplateHash string          `json:"templateHash"`
+	GeneratedAt  time.Time       `json:"generatedAt"`
+}
+
+// ManifestOptions are the options a file was generated with.
+type ManifestOptions struct {
+	StructName string `json:"structName,omitempty"`
+	Table      string `json:"table,omitempty"`
+	Service    string `json:"service,omitempty"`
+	Fields     string `json:"fields,omitempty"`
+}
+
+// LoadManifest reads the manifest at path, it returns an empty manifest if path does not exist.
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{Files: map[string]*ManifestEntry{}}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]*ManifestEntry{}
+	}
+
+	return m, nil
+}
+
+// Save writes the manifest to path. Nothing is written in dry-run mode.
+func (m *Manifest) Save(path string) error {
+	if cmdutil.DryRun {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return cmdutil.WriteFile(path, append(content, '\n'))
+}
+
+// Record records file generated with content.
+func (m *Manifest) Record(file string, content []byte, entry *ManifestEntry) {
+	entry.Hash = hashContent(content)
+	m.Files[filepath.ToSlash(file)] = entry
+}
+
+// Remove removes file from the manifest.
+func (m *Manifest) Remove(file string) {
+	delete(m.Files, filepath.ToSlash(file))
+}
+
+// Paths returns the recorded files in order.
+func (m *Manifest) Paths() []string {
+	paths := make([]string, 0, len(m.Files))
+	for path := range m.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// FileState compares file on disk with the content it was generated with.
+func (m *Manifest) FileState(file string) (string, error) {
+	entry, ok := m.Files[filepath.ToSlash(file)]
+	if !ok {
+		return FileUntracked, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return FileMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if hashContent(content) != entry.Hash {
+		return FileModified, nil
+	}
+
+	return FilePristine, nil
+}
+
+// Stale returns true if the templates of the entry changed since the file was generated.
+func (e *ManifestEntry) Stale() (bool, error) {
+	hash, err := hashTemplates(e.Templates)
+	if err != nil {
+		return false, err
+	}
+
+	return hash != e.TemplateHash, nil
+}
+
+// newManifestEntry returns the entry of a file generated from templates with the current options.
+func (o *Options) newManifestEntry(templates ...string) (*ManifestEntry, error) {
+	hash, err := hashTemplates(templates)
+	if err != nil {
+		return nil, err
+	}
+
+	service := o.Service
+	if o.ServiceName != "" {
+		service = o.ServiceName
+	}
+
+	return &ManifestEntry{
+		Templates: templates,
+		Options: ManifestOptions{
+			StructName: o.StructName,
+			Table:      o.Table,
+			Service:    service,
+			Fields:     o.FieldSpec,
+		},
+		TemplateHash: hash,
+		GeneratedAt:  time.Now(),
+	}, nil
+}
+
+// writeGeneratedFile writes content to file and records it in the manifest.
+// An existing file is overwritten without asking if it's unchanged since it was generated. If it was edited,
+// a warning is printed and the user is asked to confirm. Untracked files are confirmed only if confirmUntracked is set.
+func (o *Options) writeGeneratedFile(file string, content []byte, confirmUntracked bool, templates ...string) error {
+	manifest, err := LoadManifest(ManifestPath)
+	if err != nil {
+		return err
+	}
+
+	if cmdutil.Exists(file) && !cmdutil.Overwrite && !cmdutil.DryRun {
+		state, err := manifest.FileState(file)
+		if err != nil {
+			return err
+		}
+
+		if state == FileModified {
+			fmt.Printf("%s %s was modified since it was generated\n", ansi.Color("Warning:", "yellow"), file)
+		}
+
+		if state == FileModified || (state == FileUntracked && confirmUntracked) {
+			if err := cmdutil.ConfirmOverwrite(file); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := cmdutil.WriteFile(file, content); err != nil {
+		return err
+	}
+
+	entry, err := o.newManifestEntry(templates...)
+	if err != nil {
+		return err
+	}
+
+	manifest.Record(file, content, entry)
+
+	return manifest.Save(ManifestPath)
+}
+
+// confirmRemoveFiles warns about the files edited since they were generated and asks the user to confirm
+// deleting each of them, unless cmdutil.Overwrite is set.
+func confirmRemoveFiles(files []string) error {
+	manifest, err := LoadManifest(ManifestPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		state, err := manifest.FileState(file)
+		if err != nil {
+			return err
+		}
+		if state != FileModified {
+			continue
+		}
+
+		fmt.Printf("%s %s was modified since it was generated\n", ansi.Color("Warning:", "yellow"), file)
+		if cmdutil.Overwrite || cmdutil.DryRun {
+			continue
+		}
+		if err := cmdutil.ConfirmDelete(file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// forgetGeneratedFiles removes files from the manifest.
+func forgetGeneratedFiles(files ...string) error {
+	manifest, err := LoadManifest(ManifestPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		manifest.Remove(file)
+	}
+
+	return manifest.Save(ManifestPath)
+}
+
+// hashTemplates returns the hash of the current content of templates, templates that don't exist are skipped.
+func hashTemplates(templates []string) (string, error) {
+	h := sha256.New()
+	for _, name := range templates {
+		content, err := ReadTemplate(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(content))
+		h.Write(content)
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+// ABOUTME: Tests for the manifest of generated files.
+// ABOUTME: Verifies files are recorded, edits and outdated templates are detected, and destroy forgets files.
+package generator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+)
+
+func TestManifest_GenerateCode(t *testing.T) {
+	projectDir, _ := setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	o := &Options{FieldSpec: "title:string"}
+	if err := o.GenerateCode(string(TmplModel), "post"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	manifest, err := LoadManifest(ManifestPath)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+	entry, ok := manifest.Files[o.FilePath]
+	if !ok {
+		t.Fatalf("Expected %s recorded, got %v", o.FilePath, manifest.Paths())
+	}
+	if entry.Options.StructName != "Post" || entry.Options.Fields != "title:string" {
+		t.Errorf("Unexpected options: %+v", entry.Options)
+	}
+	if state, _ := manifest.FileState(o.FilePath); state != FilePristine {
+		t.Errorf("FileState = %q, want %q", state, FilePristine)
+	}
+
+	// Pristine files are regenerated without asking
+	if err := (&Options{FieldSpec: "title:string"}).GenerateCode(string(TmplModel), "post"); err != nil {
+		t.Fatalf("Regenerating pristine file failed: %v", err)
+	}
+
+	// Edited file
+	if err := os.WriteFile(o.FilePath, []byte("package model\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+	if state, _ := manifest.FileState(o.FilePath); state != FileModified {
+		t.Errorf("FileState = %q, want %q", state, FileModified)
+	}
+
+	// Outdated template
+	if stale, _ := entry.Stale(); stale {
+		t.Error("Expected entry not stale")
+	}
+	writeTemplate(t, projectDir, "model.tpl", "package model\n")
+	if stale, _ := entry.Stale(); !stale {
+		t.Error("Expected entry stale after the template changed")
+	}
+
+	// Deleted file
+	if err := os.Remove(o.FilePath); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if state, _ := manifest.FileState(o.FilePath); state != FileMissing {
+		t.Errorf("FileState = %q, want %q", state, FileMissing)
+	}
+	if state, _ := manifest.FileState("internal/pkg/model/other.go"); state != FileUntracked {
+		t.Errorf("FileState = %q, want %q", state, FileUntracked)
+	}
+}
+
+func TestManifest_DestroyCode(t *testing.T) {
+	setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(t.TempDir())
+
+	if err := (&Options{}).GenerateCode(string(TmplModel), "post"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if err := (&Options{}).GenerateCode(string(TmplModel), "comment"); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if err := (&Options{}).DestroyCode(string(TmplModel), "post"); err != nil {
+		t.Fatalf("DestroyCode failed: %v", err)
+	}
+
+	manifest, err := LoadManifest(ManifestPath)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+	want := []string{"internal/pkg/model/comment.go"}
+	if got := manifest.Paths(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Paths = %v, want %v", got, want)
+	}
+}
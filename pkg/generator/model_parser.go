@@ -0,0 +1,459 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// modelStruct is a GORM model declared in the model directory.
+type modelStruct struct {
+	Name    string
+	Table   string
+	Fields  []*modelField
+	Imports []string // Import specs used by the fields, e.g. `"time"` or `model "example.com/app/internal/model"`
+}
+
+// modelField is a column of a model.
+type modelField struct {
+	Name     string
+	Type     string // Type expression, qualified for use outside the model package
+	Tag      string // Raw struct tag without backquotes
+	Column   string
+	Settings map[string]string
+	Basic    string // Underlying Go type used to infer the column type, empty if unknown
+	Embed    string // Set to the embedded struct, e.g. gorm.Model, for fields of an embedded struct copied as a whole
+}
+
+// Source returns the field declaration.
+func (f *modelField) Source() string {
+	if f.Tag == "" {
+		return f.Name + " " + f.Type
+	}
+
+	return f.Name + " " + f.Type + " `" + f.Tag + "`"
+}
+
+// gormModelTags are the tags of the fields of gorm.Model.
+var gormModelTags = map[string]string{
+	"ID":        `gorm:"primarykey"`,
+	"DeletedAt": `gorm:"index"`,
+}
+
+// nullTypes maps the database/sql null types to their value type.
+var nullTypes = map[string]string{
+	"NullString":  "string",
+	"NullBool":    "bool",
+	"NullByte":    "uint8",
+	"NullInt16":   "int16",
+	"NullInt32":   "int32",
+	"NullInt64":   "int64",
+	"NullFloat64": "float64",
+	"NullTime":    "time.Time",
+}
+
+// relationSettings are the tag settings of associations, which aren't columns.
+var relationSettings = []string{"FOREIGNKEY", "REFERENCES", "MANY2MANY", "POLYMORPHIC", "JOINFOREIGNKEY", "JOINREFERENCES"}
+
+// modelPackage is a package of the model directory.
+type modelPackage struct {
+	name       string
+	importPath string
+	types      map[string]*ast.TypeSpec
+	files      map[*ast.TypeSpec]*ast.File
+	tables     map[string]string
+}
+
+// parseModels parses the models declared in dir and its sub directories. A struct is a model if it
+// embeds gorm.Model or has a TableName method. rootPackage is the import path of the project.
+func parseModels(dir, rootPackage string) ([]*modelStruct, error) {
+	var packages []*modelPackage
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+
+		pkg, err := parseModelPackage(p, path.Join(rootPackage, filepath.ToSlash(p)))
+		if err != nil {
+			return err
+		}
+		if pkg != nil {
+			packages = append(packages, pkg)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var models []*modelStruct
+	for _, pkg := range packages {
+		models = append(models, pkg.models()...)
+	}
+
+	return models, nil
+}
+
+// parseModelPackage parses the package declared in dir, nil if there is none.
+func parseModelPackage(dir, importPath string) (*modelPackage, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Several packages are declared when files are excluded by build tags, e.g. a package main
+	// generator, the one named after the directory is the model package.
+	var name string
+	switch {
+	case len(names) == 0:
+		return nil, nil
+	case pkgs[filepath.Base(dir)] != nil:
+		name = filepath.Base(dir)
+	case len(names) == 1:
+		name = names[0]
+	default:
+		return nil, fmt.Errorf("%s declares packages %s, none named %s", dir, strings.Join(names, ", "), filepath.Base(dir))
+	}
+
+	pkg := &modelPackage{
+		name:       name,
+		importPath: importPath,
+		types:      make(map[string]*ast.TypeSpec),
+		files:      make(map[*ast.TypeSpec]*ast.File),
+		tables:     make(map[string]string),
+	}
+
+	for _, file := range pkgs[name].Files {
+		pkg.collect(file)
+	}
+
+	return pkg, nil
+}
+
+// collect records the type declarations and table names of file.
+func (p *modelPackage) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok {
+					p.types[spec.Name.Name] = spec
+					p.files[spec] = file
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Name.Name != "TableName" || decl.Recv == nil || len(decl.Recv.List) != 1 {
+				continue
+			}
+
+			if table, ok := returnedString(decl); ok {
+				p.tables[receiverName(decl)] = table
+			}
+		}
+	}
+}
+
+// models returns the models of the package sorted by table name.
+func (p *modelPackage) models() []*modelStruct {
+	var models []*modelStruct
+	for name, spec := range p.types {
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+
+		table, hasTable := p.tables[name]
+		if !hasTable && !embedsGormModel(st) {
+			continue
+		}
+		if !hasTable {
+			table = schema.NamingStrategy{}.TableName(name)
+		}
+
+		model := &modelStruct{Name: name, Table: table}
+		imports := make(map[string]bool)
+		model.Fields = p.fields(st, p.files[spec], imports)
+		for spec := range imports {
+			model.Imports = append(model.Imports, spec)
+		}
+
+		models = append(models, model)
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].Table < models[j].Table
+	})
+
+	return models
+}
+
+// fields returns the columns of st, fields of embedded structs of the package are flattened.
+func (p *modelPackage) fields(st *ast.StructType, file *ast.File, imports map[string]bool) []*modelField {
+	var fields []*modelField
+	for _, field := range st.Fields.List {
+		var tag string
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		settings := schema.ParseTagSetting(reflect.StructTag(tag).Get("gorm"), ";")
+		if _, ok := settings["-"]; ok {
+			continue
+		}
+
+		// Embedded struct.
+		if len(field.Names) == 0 {
+			switch typ := exprString(field.Type); {
+			case typ == "gorm.Model":
+				for _, f := range gormModelFields {
+					basic := f.Type
+					if basic == "gorm.DeletedAt" {
+						basic = "time.Time"
+					}
+
+					fields = append(fields, &modelField{
+						Name:   f.Name,
+						Type:   f.Type,
+						Tag:    gormModelTags[f.Name],
+						Column: f.Column,
+						Basic:  basic,
+						Embed:  typ,
+					})
+				}
+				imports[`"gorm.io/gorm"`] = true
+				imports[`"time"`] = true
+			default:
+				if spec, ok := p.types[strings.TrimPrefix(typ, "*")]; ok {
+					if embedded, ok := spec.Type.(*ast.StructType); ok {
+						fields = append(fields, p.fields(embedded, p.files[spec], imports)...)
+					}
+				}
+			}
+
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() || isRelation(settings) {
+				continue
+			}
+			if _, ok := settings["EMBEDDED"]; ok {
+				continue
+			}
+
+			typ, basic, ok := p.resolveType(field.Type)
+			if !ok {
+				if _, serialized := settings["SERIALIZER"]; !serialized {
+					continue
+				}
+			}
+
+			column := settings["COLUMN"]
+			if column == "" {
+				column = schema.NamingStrategy{}.ColumnName("", name.Name)
+			}
+
+			fields = append(fields, &modelField{
+				Name:     name.Name,
+				Type:     typ,
+				Tag:      tag,
+				Column:   column,
+				Settings: settings,
+				Basic:    basic,
+			})
+
+			for _, spec := range usedImports(field.Type, file) {
+				imports[spec] = true
+			}
+			if typ != exprString(field.Type) {
+				imports[p.importSpec()] = true
+			}
+		}
+	}
+
+	for _, field := range fields {
+		if field.Column == "" {
+			field.Column = schema.NamingStrategy{}.ColumnName("", field.Name)
+		}
+		if field.Settings == nil {
+			field.Settings = schema.ParseTagSetting(reflect.StructTag(field.Tag).Get("gorm"), ";")
+		}
+	}
+
+	return fields
+}
+
+// resolveType returns the type expression of a column qualified for use outside the package and
+// its underlying Go type. ok is false if expr isn't a column, e.g. an association.
+func (p *modelPackage) resolveType(expr ast.Expr) (typ string, basic string, ok bool) {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		typ, basic, ok = p.resolveType(expr.X)
+		return "*" + typ, basic, ok
+	case *ast.Ident:
+		if basic, ok := basicType(expr.Name); ok {
+			return expr.Name, basic, true
+		}
+
+		spec, declared := p.types[expr.Name]
+		if !declared {
+			return expr.Name, "", false
+		}
+		if _, isStruct := spec.Type.(*ast.StructType); isStruct {
+			return p.name + "." + expr.Name, "", false
+		}
+
+		_, basic, ok = p.resolveType(spec.Type)
+		return p.name + "." + expr.Name, basic, ok
+	case *ast.SelectorExpr:
+		typ = exprString(expr)
+		switch {
+		case typ == "time.Time" || typ == "gorm.DeletedAt":
+			basic = "time.Time"
+		case strings.HasPrefix(typ, "sql.Null"):
+			basic = nullTypes[expr.Sel.Name]
+		}
+
+		return typ, basic, true
+	case *ast.ArrayType:
+		if elt, ok := expr.Elt.(*ast.Ident); ok && expr.Len == nil && (elt.Name == "byte" || elt.Name == "uint8") {
+			return "[]byte", "[]byte", true
+		}
+		if expr.Len == nil {
+			elt, _, _ := p.resolveType(expr.Elt)
+			return "[]" + elt, "", false
+		}
+
+		return exprString(expr), "", false
+	default:
+		return exprString(expr), "", false
+	}
+}
+
+// importSpec returns the import spec of the package.
+func (p *modelPackage) importSpec() string {
+	if path.Base(p.importPath) == p.name {
+		return strconv.Quote(p.importPath)
+	}
+
+	return p.name + " " + strconv.Quote(p.importPath)
+}
+
+// basicType returns the normalized predeclared type of name.
+func basicType(name string) (string, bool) {
+	switch name {
+	case "byte":
+		return "uint8", true
+	case "rune":
+		return "int32", true
+	case "string", "bool", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return name, true
+	}
+
+	return "", false
+}
+
+func isRelation(settings map[string]string) bool {
+	for _, key := range relationSettings {
+		if _, ok := settings[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func embedsGormModel(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 && exprString(field.Type) == "gorm.Model" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// usedImports returns the import specs of file referenced by expr.
+func usedImports(expr ast.Expr, file *ast.File) []string {
+	var specs []string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		for _, imp := range file.Imports {
+			importPath, _ := strconv.Unquote(imp.Path.Value)
+			name := path.Base(importPath)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name != x.Name {
+				continue
+			}
+
+			spec := imp.Path.Value
+			if imp.Name != nil {
+				spec = imp.Name.Name + " " + spec
+			}
+			specs = append(specs, spec)
+		}
+
+		return false
+	})
+
+	return specs
+}
+
+// returnedString returns the string literal returned by fn, e.g. the table name of a TableName method.
+func returnedString(fn *ast.FuncDecl) (string, bool) {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return "", false
+	}
+
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	value, err := strconv.Unquote(lit.Value)
+
+	return value, err == nil
+}
+
+func exprString(expr ast.Expr) string {
+	var buf strings.Builder
+	_ = printer.Fprint(&buf, token.NewFileSet(), expr)
+
+	return buf.String()
+}
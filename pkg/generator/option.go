@@ -1,5 +1,7 @@
 package generator
 
+import "strings"
+
 type Options struct {
 	// Code template
 	Name              string
@@ -9,6 +11,7 @@ type Options struct {
 	CodeTemplate      string
 	InterfaceTemplate string
 	RegisterTemplate  string
+	TestTemplate      string
 
 	// Code attributes - variable
 	PackageName        string
@@ -39,19 +42,34 @@ type Options struct {
 	NoRouter       bool
 	NoHandler      bool
 
+	// Generate *_test.go along with the code
+	WithTests bool
+
 	// Service selection
 	Service string // Target service name for path inference
 
 	// Generate by gorm.gen
 	Table           string
+	FieldSpec       string // Inline field definitions, e.g. "name:string:unique,age:int:nullable"
 	FieldTemplate   string
 	Fields          string
 	MainFields      string
 	UpdatableFields string
 	MetaFields      []*Field
 
+	// Factory
+	FactoryModel  string          // Model struct the factory makes, e.g. UserM
+	FactoryFields []*FactoryField // Set by GetFactoryFields
+
+	// Store test
+	StoreTestFields []*FactoryField // Set by GetStoreTestFields
+
+	// Seeder
+	SeederRegister bool // Register the seeder in init(), unset when the seeder package runs them by RunSeeders
+
 	// Migration
-	TimeStr string
+	TimeStr    string
+	SchemaDiff *SchemaDiff // Set by GenerateMigrationDiff
 }
 
 func (o *Options) SetName(name string) *Options {
@@ -60,6 +78,16 @@ func (o *Options) SetName(name string) *Options {
 	return o
 }
 
+// UseDB returns true if fields should be read from db table instead of --fields.
+func (o *Options) UseDB() bool {
+	return o.Table != "" && o.FieldSpec == ""
+}
+
+// TestFilePath returns the path of the test file generated along with FilePath.
+func (o *Options) TestFilePath() string {
+	return strings.TrimSuffix(o.FilePath, ".go") + "_test.go"
+}
+
 func (o *Options) ReSetDirectory() *Options {
 	o.Directory = ""
 	o.PackageName = ""
@@ -1,16 +1,22 @@
 package generator
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
-	"os"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/mgutz/ansi"
+	"golang.org/x/tools/go/ast/astutil"
 
 	"github.com/bingo-project/bingoctl/pkg/config"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
 )
 
 func (o *Options) Register(registry config.Registry, interfaceTemplate, registerTemplate, importPath string) error {
@@ -24,6 +30,86 @@ func (o *Options) Register(registry config.Registry, interfaceTemplate, register
 	generatedDir := strings.TrimSuffix(o.Directory, "/")
 	samePackage := registryDir == generatedDir
 
+	interfaceTemplate = o.replaceRegistryTemplate(interfaceTemplate)
+	registerTemplate = o.replaceRegistryTemplate(registerTemplate)
+
+	content, err := cmdutil.ReadFile(registry.Filepath)
+	if err != nil {
+		return err
+	}
+
+	// 注册 interface
+	newContent, err := RegisterInterface(registry.Interface, string(content), interfaceTemplate, registerTemplate, importPath, samePackage)
+	if err != nil {
+		return err
+	}
+
+	if newContent == string(content) {
+		if !cmdutil.DryRun {
+			fmt.Printf("%s %s\n", ansi.Color("Already registered:", "yellow"), registry.Filepath)
+		}
+
+		return nil
+	}
+
+	err = cmdutil.WriteFile(registry.Filepath, []byte(newContent))
+	if err != nil {
+		return err
+	}
+
+	if !cmdutil.DryRun {
+		fmt.Printf("%s %s\n", ansi.Color("Registered:", "green"), registry.Filepath)
+	}
+
+	return nil
+}
+
+// Unregister removes the interface method and register function added by Register from the registry file,
+// and the import if it's no longer used.
+func (o *Options) Unregister(registry config.Registry, interfaceTemplate, registerTemplate, importPath string) error {
+	if registry.Filepath == "" {
+		return nil
+	}
+
+	registryDir := filepath.Dir(registry.Filepath)
+	generatedDir := strings.TrimSuffix(o.Directory, "/")
+	samePackage := registryDir == generatedDir
+
+	interfaceTemplate = o.replaceRegistryTemplate(interfaceTemplate)
+	registerTemplate = o.replaceRegistryTemplate(registerTemplate)
+
+	content, err := cmdutil.ReadFile(registry.Filepath)
+	if err != nil {
+		return err
+	}
+
+	newContent, err := UnregisterInterface(registry.Interface, string(content), interfaceTemplate, registerTemplate, importPath, samePackage)
+	if err != nil {
+		return err
+	}
+
+	if newContent == string(content) {
+		if !cmdutil.DryRun {
+			fmt.Printf("%s %s\n", ansi.Color("Not registered:", "yellow"), registry.Filepath)
+		}
+
+		return nil
+	}
+
+	err = cmdutil.WriteFile(registry.Filepath, []byte(newContent))
+	if err != nil {
+		return err
+	}
+
+	if !cmdutil.DryRun {
+		fmt.Printf("%s %s\n", ansi.Color("Unregistered:", "green"), registry.Filepath)
+	}
+
+	return nil
+}
+
+// replaceRegistryTemplate replaces the code attributes in registry templates.
+func (o *Options) replaceRegistryTemplate(tmpl string) string {
 	// Package
 	pkg := ""
 	if o.PackageName != o.Name {
@@ -37,86 +123,346 @@ func (o *Options) Register(registry config.Registry, interfaceTemplate, register
 	replaces["{{.StructNamePlural}}"] = o.StructNamePlural
 	replaces["{{.VariableName}}"] = o.VariableName
 	replaces["{{.VariableNameSnake}}"] = o.VariableNameSnake
+	replaces["{{.VariableNamePlural}}"] = o.VariableNamePlural
 
 	for search, replace := range replaces {
-		interfaceTemplate = strings.ReplaceAll(interfaceTemplate, search, replace)
-		registerTemplate = strings.ReplaceAll(registerTemplate, search, replace)
+		tmpl = strings.ReplaceAll(tmpl, search, replace)
 	}
 
-	content, err := os.ReadFile(registry.Filepath)
+	return tmpl
+}
+
+// RegisterInterface adds the interface method to the named interface, the register function after
+// the methods of the same receiver and the import path to the import declarations.
+// Parts that are already registered are left untouched, so registering twice is a no-op.
+func RegisterInterface(name, content, interfaceTemplate, registerTemplate, importPath string, samePackage bool) (data string, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to parse registry file: %w", err)
 	}
 
-	// 注册 interface
-	newContent, err := RegisterInterface(registry.Interface, string(content), interfaceTemplate, registerTemplate, importPath, samePackage)
+	iface := findInterface(file, name)
+	if iface == nil {
+		return "", fmt.Errorf("interface %s not found", name)
+	}
+
+	methodName, err := parseInterfaceMethod(interfaceTemplate)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	err = os.WriteFile(registry.Filepath, []byte(newContent), 0644)
+	registerFuncs, err := parseFuncDecls(registerTemplate)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	fmt.Printf("%s %s\n", ansi.Color("Registered:", "green"), registry.Filepath)
+	var edits []textEdit
 
-	return nil
+	// Register interface method before the closing brace
+	if !hasInterfaceMethod(iface, methodName) {
+		edits = append(edits, interfaceMethodEdit(fset, content, iface, strings.TrimSpace(interfaceTemplate)))
+	}
+
+	// Register function after the last function of the same receiver
+	if !hasFuncDecls(file, registerFuncs) {
+		offset := fset.Position(file.End()).Offset
+		if last := lastFuncOfReceiver(file, receiverName(registerFuncs[0])); last != nil {
+			offset = fset.Position(last.End()).Offset
+		}
+
+		edits = append(edits, insertEdit(offset, "\n\n"+strings.TrimSpace(registerTemplate)+"\n"))
+	}
+
+	newContent := applyEdits(content, edits)
+
+	// Skip import if same package to avoid self-referencing import
+	if samePackage || importPath == "" {
+		return formatIfChanged(content, newContent)
+	}
+
+	fset = token.NewFileSet()
+	file, err = parser.ParseFile(fset, "", newContent, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse registered file: %w", err)
+	}
+
+	if !astutil.AddImport(fset, file, importPath) && len(edits) == 0 {
+		return content, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
 }
 
-func RegisterInterface(name, content, interfaceTemplate, registerTemplate, importPath string, samePackage bool) (data string, err error) {
-	// Check if already registered
-	if strings.Contains(content, interfaceTemplate) {
-		return "", errors.New("interface already registered: " + interfaceTemplate)
+// UnregisterInterface removes what RegisterInterface added: the interface method, the register function
+// and the import path if nothing else in the file uses it. Parts that are not registered are ignored.
+func UnregisterInterface(name, content, interfaceTemplate, registerTemplate, importPath string, samePackage bool) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse registry file: %w", err)
+	}
+
+	iface := findInterface(file, name)
+	if iface == nil {
+		return "", fmt.Errorf("interface %s not found", name)
 	}
 
-	// Register
-	pattern := fmt.Sprintf("type %s interface {([^}]*)}", name)
-	match, err := Match(pattern, content)
+	methodName, err := parseInterfaceMethod(interfaceTemplate)
 	if err != nil {
 		return "", err
 	}
 
-	str := strings.TrimRight(match, "}")
-	str = str + "\t" + interfaceTemplate + "\n}"
+	registerFuncs, err := parseFuncDecls(registerTemplate)
+	if err != nil {
+		return "", err
+	}
 
-	newContent := strings.Replace(content, match, str, 1)
-	newContent = newContent + "\n" + registerTemplate
+	var edits []textEdit
+	for _, method := range iface.Methods.List {
+		if len(method.Names) == 1 && method.Names[0].Name == methodName {
+			edits = append(edits, removeEdit(fset, content, method.Doc, method))
+		}
+	}
 
-	// Skip import if same package to avoid self-referencing import
+	for _, fn := range registerFuncs {
+		if decl := findFuncDecl(file, receiverName(fn), fn.Name.Name); decl != nil {
+			edits = append(edits, removeEdit(fset, content, decl.Doc, decl))
+		}
+	}
+
+	if len(edits) == 0 {
+		return content, nil
+	}
+
+	// Same package registry has no import to remove
 	if samePackage {
-		return newContent, nil
+		importPath = ""
+	}
+
+	return removeUnusedImports(applyEdits(content, edits), importPath)
+}
+
+// removeUnusedImports formats content after removing the import paths that are no longer used.
+func removeUnusedImports(content string, paths ...string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse unregistered file: %w", err)
+	}
+
+	for _, path := range paths {
+		if path != "" && !astutil.UsesImport(file, path) {
+			astutil.DeleteImport(fset, file, path)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// textEdit replaces content[offset:end] with text, it's an insertion if end equals offset.
+type textEdit struct {
+	offset int
+	end    int
+	text   string
+}
+
+func insertEdit(offset int, text string) textEdit {
+	return textEdit{offset: offset, end: offset, text: text}
+}
+
+func applyEdits(content string, edits []textEdit) string {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].offset > edits[j].offset })
+	for _, edit := range edits {
+		content = content[:edit.offset] + edit.text + content[edit.end:]
 	}
 
-	// Import path
-	if strings.Contains(newContent, importPath) {
-		return newContent, nil
+	return content
+}
+
+func formatIfChanged(content, newContent string) (string, error) {
+	if content == newContent {
+		return content, nil
 	}
 
-	pattern = `import\s?\(([^}]*?)\)`
-	match, err = Match(pattern, content)
+	formatted, err := format.Source([]byte(newContent))
 	if err != nil {
 		return "", err
 	}
 
-	str = strings.TrimRight(match, ")")
-	str = fmt.Sprintf("%s\t\"%s\"\n)", str, importPath)
+	return string(formatted), nil
+}
+
+func findInterface(file *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok && typeSpec.Name.Name == name {
+				return iface
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasInterfaceMethod(iface *ast.InterfaceType, name string) bool {
+	for _, method := range iface.Methods.List {
+		for _, ident := range method.Names {
+			if ident.Name == name {
+				return true
+			}
+		}
+	}
 
-	newContent = strings.Replace(newContent, match, str, 1)
+	return false
+}
 
-	return newContent, nil
+// interfaceMethodEdit inserts the method on its own line before the closing brace of the interface.
+func interfaceMethodEdit(fset *token.FileSet, content string, iface *ast.InterfaceType, method string) textEdit {
+	return closingBraceEdit(content, fset.Position(iface.Methods.Closing).Offset, "\t"+method+"\n")
 }
 
-func Match(pattern, content string) (string, error) {
-	reg := regexp.MustCompile(pattern)
+// closingBraceEdit inserts text on its own line before the closing brace at offset.
+func closingBraceEdit(content string, closing int, text string) textEdit {
+	lineStart := strings.LastIndex(content[:closing], "\n") + 1
 
-	// 根据规则提取关键信息
-	results := reg.FindAllString(content, -1)
-	if len(results) == 0 {
-		return "", errors.New("not matched")
+	// Closing brace on its own line
+	if strings.TrimSpace(content[lineStart:closing]) == "" {
+		return insertEdit(lineStart, text)
 	}
 
-	match := results[0]
+	return insertEdit(closing, "\n"+text)
+}
+
+// removeEdit removes node with its doc comment. If node is on its own lines, the lines are removed
+// together with a blank line before them, otherwise only the node itself is removed.
+func removeEdit(fset *token.FileSet, content string, doc *ast.CommentGroup, node ast.Node) textEdit {
+	start := fset.Position(node.Pos()).Offset
+	if doc != nil {
+		start = fset.Position(doc.Pos()).Offset
+	}
+	end := fset.Position(node.End()).Offset
+
+	lineStart := strings.LastIndex(content[:start], "\n") + 1
+	lineEnd := len(content)
+	if i := strings.Index(content[end:], "\n"); i >= 0 {
+		lineEnd = end + i + 1
+	}
+
+	rest := strings.TrimSpace(content[end:lineEnd])
+	if strings.TrimSpace(content[lineStart:start]) != "" || (rest != "" && !strings.HasPrefix(rest, "//")) {
+		return textEdit{offset: start, end: end}
+	}
+
+	if lineStart > 0 {
+		prevLineStart := strings.LastIndex(content[:lineStart-1], "\n") + 1
+		if strings.TrimSpace(content[prevLineStart:lineStart]) == "" {
+			lineStart = prevLineStart
+		}
+	}
+
+	return textEdit{offset: lineStart, end: lineEnd}
+}
+
+func parseInterfaceMethod(tmpl string) (string, error) {
+	src := "package p\ntype _ interface {\n" + tmpl + "\n}"
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return "", fmt.Errorf("invalid interface template %q: %w", tmpl, err)
+	}
+
+	iface := findInterface(file, "_")
+	if len(iface.Methods.List) != 1 || len(iface.Methods.List[0].Names) != 1 {
+		return "", fmt.Errorf("interface template must define exactly one method: %q", tmpl)
+	}
+
+	return iface.Methods.List[0].Names[0].Name, nil
+}
+
+func parseFuncDecls(tmpl string) ([]*ast.FuncDecl, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+tmpl, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry template: %w", err)
+	}
+
+	var funcs []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			funcs = append(funcs, fn)
+		}
+	}
+
+	if len(funcs) == 0 {
+		return nil, errors.New("registry template must define a function")
+	}
+
+	return funcs, nil
+}
+
+func hasFuncDecls(file *ast.File, funcs []*ast.FuncDecl) bool {
+	for _, fn := range funcs {
+		if findFuncDecl(file, receiverName(fn), fn.Name.Name) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func findFuncDecl(file *ast.File, receiver, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name && receiverName(fn) == receiver {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+func lastFuncOfReceiver(file *ast.File, receiver string) *ast.FuncDecl {
+	if receiver == "" {
+		return nil
+	}
+
+	var last *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && receiverName(fn) == receiver {
+			last = fn
+		}
+	}
+
+	return last
+}
+
+// receiverName returns the receiver type name of fn, e.g. "datastore" for (store *datastore).
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
 
-	return match, nil
+	return ""
 }
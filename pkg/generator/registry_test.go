@@ -0,0 +1,185 @@
+// ABOUTME: Tests for registering generated code into registry files.
+// ABOUTME: Verifies interface methods, functions and imports are inserted once via go/ast.
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	testInterfaceTemplate = "Post() post.PostStore"
+	testRegisterTemplate  = "func (store *datastore) Post() post.PostStore {\n\treturn post.NewPostStore(store)\n}"
+	testImportPath        = "example.com/app/internal/pkg/store/post"
+)
+
+const testRegistry = `package store
+
+import (
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// IStore defines the store registry.
+type IStore interface {
+	// DB returns the db instance.
+	DB() *gorm.DB
+	Hooks() map[string]func() // braces inside the interface
+}
+
+type datastore struct {
+	db   *gorm.DB
+	once sync.Once
+}
+
+func (store *datastore) DB() *gorm.DB {
+	return store.db
+}
+
+func (store *datastore) Hooks() map[string]func() {
+	return map[string]func(){}
+}
+
+// NewStore creates the store.
+func NewStore(db *gorm.DB) *datastore {
+	return &datastore{db: db}
+}
+`
+
+func TestRegisterInterface(t *testing.T) {
+	got, err := RegisterInterface("IStore", testRegistry, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("RegisterInterface failed: %v", err)
+	}
+
+	expects := []string{
+		"\t\"example.com/app/internal/pkg/store/post\"\n\t\"gorm.io/gorm\"\n)",
+		"\tHooks() map[string]func() // braces inside the interface\n\tPost() post.PostStore\n}",
+		"\treturn map[string]func(){}\n}\n\nfunc (store *datastore) Post() post.PostStore {\n\treturn post.NewPostStore(store)\n}\n\n// NewStore creates the store.",
+	}
+	for _, expect := range expects {
+		if !strings.Contains(got, expect) {
+			t.Errorf("Expected registry to contain %q, got:\n%s", expect, got)
+		}
+	}
+}
+
+func TestRegisterInterface_Idempotent(t *testing.T) {
+	once, err := RegisterInterface("IStore", testRegistry, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("RegisterInterface failed: %v", err)
+	}
+
+	twice, err := RegisterInterface("IStore", once, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("RegisterInterface on registered file failed: %v", err)
+	}
+
+	if twice != once {
+		t.Errorf("Expected no changes on second run, got:\n%s", twice)
+	}
+}
+
+func TestRegisterInterface_Imports(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		expect  string
+	}{
+		{
+			name:    "single import",
+			content: "package store\n\nimport \"gorm.io/gorm\"\n\ntype IStore interface {\n\tDB() *gorm.DB\n}\n\ntype datastore struct{}\n",
+			expect:  "import (\n\t\"example.com/app/internal/pkg/store/post\"\n\t\"gorm.io/gorm\"\n)",
+		},
+		{
+			name:    "no import",
+			content: "package store\n\ntype IStore interface{}\n\ntype datastore struct{}\n",
+			expect:  "import \"example.com/app/internal/pkg/store/post\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RegisterInterface("IStore", tt.content, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+			if err != nil {
+				t.Fatalf("RegisterInterface failed: %v", err)
+			}
+
+			if !strings.Contains(got, tt.expect) {
+				t.Errorf("Expected registry to contain %q, got:\n%s", tt.expect, got)
+			}
+			if !strings.Contains(got, "\tPost() post.PostStore\n}") {
+				t.Errorf("Expected interface method registered, got:\n%s", got)
+			}
+			if !strings.HasSuffix(got, "func (store *datastore) Post() post.PostStore {\n\treturn post.NewPostStore(store)\n}\n") {
+				t.Errorf("Expected register function at the end of file, got:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestRegisterInterface_SamePackage(t *testing.T) {
+	got, err := RegisterInterface("IStore", testRegistry, "Post() PostStore", "func (store *datastore) Post() PostStore {\n\treturn NewPostStore(store)\n}", testImportPath, true)
+	if err != nil {
+		t.Fatalf("RegisterInterface failed: %v", err)
+	}
+
+	if strings.Contains(got, testImportPath) {
+		t.Errorf("Expected no self import for same package, got:\n%s", got)
+	}
+	if !strings.Contains(got, "\tPost() PostStore\n}") {
+		t.Errorf("Expected interface method registered, got:\n%s", got)
+	}
+}
+
+func TestRegisterInterface_NotFound(t *testing.T) {
+	_, err := RegisterInterface("IBiz", testRegistry, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err == nil {
+		t.Fatal("Expected error for missing interface, got nil")
+	}
+}
+
+func TestUnregisterInterface(t *testing.T) {
+	registered, err := RegisterInterface("IStore", testRegistry, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("RegisterInterface failed: %v", err)
+	}
+
+	got, err := UnregisterInterface("IStore", registered, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("UnregisterInterface failed: %v", err)
+	}
+	if got != testRegistry {
+		t.Errorf("Expected registry restored, got:\n%s", got)
+	}
+
+	// Not registered
+	again, err := UnregisterInterface("IStore", got, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("UnregisterInterface on unregistered file failed: %v", err)
+	}
+	if again != got {
+		t.Errorf("Expected no changes on second run, got:\n%s", again)
+	}
+}
+
+func TestUnregisterInterface_KeepsUsedImport(t *testing.T) {
+	registered, err := RegisterInterface("IStore", testRegistry, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("RegisterInterface failed: %v", err)
+	}
+	registered += "\nvar _ post.PostStore\n"
+
+	got, err := UnregisterInterface("IStore", registered, testInterfaceTemplate, testRegisterTemplate, testImportPath, false)
+	if err != nil {
+		t.Fatalf("UnregisterInterface failed: %v", err)
+	}
+
+	if !strings.Contains(got, testImportPath) {
+		t.Errorf("Expected import kept while still used, got:\n%s", got)
+	}
+	if strings.Contains(got, "Post() post.PostStore") {
+		t.Errorf("Expected interface method and register function removed, got:\n%s", got)
+	}
+}
@@ -0,0 +1,481 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bingo-project/component-base/cli/console"
+	"github.com/mgutz/ansi"
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+// ErrRouterGroupNotFound is returned when the router file has no route group with the configured prefix.
+var ErrRouterGroupNotFound = errors.New("route group not found in router file")
+
+// RouterGroup is the route group of the router file the handler routes are registered to.
+type RouterGroup struct {
+	// Prefix is the path of the group, e.g. /v1.
+	Prefix string
+
+	// HandlerArgs are the handler constructor arguments used when the router file
+	// has no handler constructor call to copy them from.
+	HandlerArgs string
+}
+
+// ConfigRouterGroup returns the route group set by registries.routerGroup and registries.handlerArgs.
+func ConfigRouterGroup() RouterGroup {
+	return RouterGroup{Prefix: config.Cfg.GetRouterGroup(), HandlerArgs: config.Cfg.GetHandlerArgs()}
+}
+
+var (
+	handlerConstructorReg = regexp.MustCompile(`^New\w*Handler$`)
+	routeMethods          = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "Any", "Handle"}
+)
+
+// RegisterRoutes registers the routes of the generated handler into the router file. A router file
+// without the route group only gets a warning, as the handler is generated already.
+func (o *Options) RegisterRoutes(routerPath, routesTemplate string) error {
+	if routerPath == "" || routesTemplate == "" {
+		return nil
+	}
+
+	routesTemplate, handlerImport := o.replaceRoutesTemplate(routerPath, routesTemplate)
+
+	content, err := cmdutil.ReadFile(routerPath)
+	if err != nil {
+		return err
+	}
+
+	newContent, err := RegisterRouterGroup(string(content), routesTemplate, ConfigRouterGroup(), handlerImport, o.RootPackage+"/"+o.StorePath)
+	if errors.Is(err, ErrRouterGroupNotFound) {
+		console.Warn(fmt.Sprintf("%s: %v, register the routes manually or set registries.routerGroup.", routerPath, err))
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if newContent == string(content) {
+		if !cmdutil.DryRun {
+			fmt.Printf("%s %s\n", ansi.Color("Routes already registered:", "yellow"), routerPath)
+		}
+
+		return nil
+	}
+
+	err = cmdutil.WriteFile(routerPath, []byte(newContent))
+	if err != nil {
+		return err
+	}
+
+	if !cmdutil.DryRun {
+		fmt.Printf("%s %s\n", ansi.Color("Registered routes:", "green"), routerPath)
+	}
+
+	return nil
+}
+
+// UnregisterRoutes removes the routes registered by RegisterRoutes from the router file,
+// and the imports that are no longer used.
+func (o *Options) UnregisterRoutes(routerPath, routesTemplate string) error {
+	if routerPath == "" || routesTemplate == "" {
+		return nil
+	}
+
+	routesTemplate, handlerImport := o.replaceRoutesTemplate(routerPath, routesTemplate)
+
+	content, err := cmdutil.ReadFile(routerPath)
+	if err != nil {
+		return err
+	}
+
+	newContent, err := UnregisterRouterGroup(string(content), routesTemplate, ConfigRouterGroup(), handlerImport, o.RootPackage+"/"+o.StorePath)
+	if err != nil {
+		return err
+	}
+
+	if newContent == string(content) {
+		if !cmdutil.DryRun {
+			fmt.Printf("%s %s\n", ansi.Color("Routes not registered:", "yellow"), routerPath)
+		}
+
+		return nil
+	}
+
+	err = cmdutil.WriteFile(routerPath, []byte(newContent))
+	if err != nil {
+		return err
+	}
+
+	if !cmdutil.DryRun {
+		fmt.Printf("%s %s\n", ansi.Color("Unregistered routes:", "green"), routerPath)
+	}
+
+	return nil
+}
+
+// replaceRoutesTemplate replaces the code attributes in routes template, and returns it with the handler
+// import path, which is empty if the router is in the same package as the handler.
+func (o *Options) replaceRoutesTemplate(routerPath, routesTemplate string) (string, string) {
+	handlerDir := strings.TrimSuffix(o.Directory, "/")
+	samePackage := filepath.Dir(routerPath) == handlerDir
+
+	pkg := o.PackageName + "."
+	handlerImport := o.RootPackage + "/" + handlerDir
+	if samePackage {
+		pkg = ""
+		handlerImport = ""
+	}
+
+	routesTemplate = strings.ReplaceAll(routesTemplate, "{{.Package}}", pkg)
+
+	return o.replaceRegistryTemplate(routesTemplate), handlerImport
+}
+
+// RegisterRouterGroup inserts routes into the route group of the router file content.
+//
+// {{.RouterGroup}} in routes is replaced with the variable of the route group, {{.HandlerArgs}} with
+// the arguments of an existing handler constructor call, or routerGroup.HandlerArgs if there is none.
+// If any of the routes is already registered, content is returned unchanged.
+func RegisterRouterGroup(content, routes string, routerGroup RouterGroup, handlerImport, storeImport string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse router file: %w", err)
+	}
+
+	group, closing := findRouterGroup(fset, file, routerGroup.Prefix)
+	if group == "" {
+		return "", fmt.Errorf("%w: %q", ErrRouterGroupNotFound, routerGroup.Prefix)
+	}
+
+	handlerArgs := findHandlerArgs(fset, content, file)
+	useDefaultArgs := handlerArgs == ""
+	if useDefaultArgs {
+		handlerArgs = routerGroup.HandlerArgs
+	}
+
+	routes = strings.ReplaceAll(routes, "{{.RouterGroup}}", group)
+	routes = strings.ReplaceAll(routes, "{{.HandlerArgs}}", handlerArgs)
+	routes = strings.TrimSpace(routes)
+
+	newRoutes, err := parseRoutes(routes, group)
+	if err != nil {
+		return "", err
+	}
+
+	// Skip if routes already exist
+	existing := collectRoutes(file, group)
+	for route := range newRoutes {
+		if existing[route] {
+			return content, nil
+		}
+	}
+
+	text := routes + "\n"
+	if before := strings.TrimRight(content[:strings.LastIndex(content[:closing], "\n")+1], " \t\n"); !strings.HasSuffix(before, "{") {
+		text = "\n" + text
+	}
+	content = applyEdits(content, []textEdit{closingBraceEdit(content, closing, text)})
+
+	fset = token.NewFileSet()
+	file, err = parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse registered router file: %w", err)
+	}
+
+	if handlerImport != "" {
+		astutil.AddImport(fset, file, handlerImport)
+	}
+	if useDefaultArgs && storeImport != "" {
+		astutil.AddImport(fset, file, storeImport)
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// UnregisterRouterGroup removes the statements of routes from the router file content: the routes
+// registered on the route group and the variables they define, e.g. the handler.
+// The handler and store imports are removed if they are no longer used.
+func UnregisterRouterGroup(content, routes string, routerGroup RouterGroup, handlerImport, storeImport string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse router file: %w", err)
+	}
+
+	group, _ := findRouterGroup(fset, file, routerGroup.Prefix)
+	if group == "" {
+		return content, nil
+	}
+
+	routes = strings.ReplaceAll(routes, "{{.RouterGroup}}", group)
+	routes = strings.ReplaceAll(routes, "{{.HandlerArgs}}", routerGroup.HandlerArgs)
+
+	routeSet, err := parseRoutes(routes, group)
+	if err != nil {
+		return "", err
+	}
+
+	variables, err := parseDefinedVariables(routes)
+	if err != nil {
+		return "", err
+	}
+
+	var edits []textEdit
+	ast.Inspect(file, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+
+		for _, stmt := range block.List {
+			if isRouteStmt(stmt, group, routeSet) || definesVariable(stmt, variables) {
+				edits = append(edits, removeEdit(fset, content, nil, stmt))
+			}
+		}
+
+		return true
+	})
+
+	if len(edits) == 0 {
+		return content, nil
+	}
+
+	return removeUnusedImports(applyEdits(content, edits), handlerImport, storeImport)
+}
+
+// isRouteStmt reports whether stmt registers one of routes on group.
+func isRouteStmt(stmt ast.Stmt, group string, routes map[string]bool) bool {
+	exprStmt, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+
+	for route := range collectRoutes(exprStmt, group) {
+		if routes[route] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseDefinedVariables returns the variables defined with := in routes.
+func parseDefinedVariables(routes string) (map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\nfunc _() {\n"+routes+"\n}", 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid router registry template: %w", err)
+	}
+
+	variables := make(map[string]bool)
+	for _, stmt := range file.Decls[0].(*ast.FuncDecl).Body.List {
+		if assign, ok := stmt.(*ast.AssignStmt); ok && assign.Tok == token.DEFINE {
+			for _, lhs := range assign.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					variables[ident.Name] = true
+				}
+			}
+		}
+	}
+
+	return variables, nil
+}
+
+// definesVariable reports whether stmt defines one of variables with :=.
+func definesVariable(stmt ast.Stmt, variables map[string]bool) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE {
+		return false
+	}
+
+	for _, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && variables[ident.Name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findRouterGroup finds the variable assigned by Group(prefix) and the offset of the closing brace
+// routes should be inserted before: the block following the assignment, e.g. v1 := g.Group("/v1") { ... },
+// or the end of the block containing the assignment.
+func findRouterGroup(fset *token.FileSet, file *ast.File, prefix string) (group string, closing int) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		if group != "" {
+			return false
+		}
+
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+
+		for i, stmt := range block.List {
+			name := groupAssignName(stmt, prefix)
+			if name == "" {
+				continue
+			}
+
+			group = name
+			closing = fset.Position(block.Rbrace).Offset
+			if i+1 < len(block.List) {
+				if next, ok := block.List[i+1].(*ast.BlockStmt); ok {
+					closing = fset.Position(next.Rbrace).Offset
+				}
+			}
+
+			return false
+		}
+
+		return true
+	})
+
+	return group, closing
+}
+
+// groupAssignName returns the variable name if stmt is like v1 := g.Group("/v1").
+func groupAssignName(stmt ast.Stmt, prefix string) string {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return ""
+	}
+
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return ""
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Group" {
+		return ""
+	}
+
+	path, ok := stringLit(call.Args[0])
+	if !ok || "/"+strings.Trim(path, "/") != "/"+strings.Trim(prefix, "/") {
+		return ""
+	}
+
+	return ident.Name
+}
+
+// findHandlerArgs returns the source of the arguments of the first handler constructor call.
+func findHandlerArgs(fset *token.FileSet, content string, file *ast.File) (args string) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		if args != "" {
+			return false
+		}
+
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		var name string
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			name = fun.Name
+		case *ast.SelectorExpr:
+			name = fun.Sel.Name
+		}
+
+		if handlerConstructorReg.MatchString(name) {
+			start := fset.Position(call.Args[0].Pos()).Offset
+			end := fset.Position(call.Args[len(call.Args)-1].End()).Offset
+			args = content[start:end]
+		}
+
+		return true
+	})
+
+	return args
+}
+
+// parseRoutes returns the routes registered on group by the statements in routes.
+func parseRoutes(routes, group string) (map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\nfunc _() {\n"+routes+"\n}", 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid router registry template: %w", err)
+	}
+
+	result := collectRoutes(file, group)
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no route registered on %s in router registry template", group)
+	}
+
+	return result, nil
+}
+
+// collectRoutes returns routes like "GET /posts" registered on group.
+func collectRoutes(node ast.Node, group string) map[string]bool {
+	routes := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !slices.Contains(routeMethods, sel.Sel.Name) {
+			return true
+		}
+
+		if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != group {
+			return true
+		}
+
+		args := call.Args
+		method := sel.Sel.Name
+		if method == "Handle" && len(args) > 1 {
+			method, _ = stringLit(args[0])
+			args = args[1:]
+		}
+
+		if path, ok := stringLit(args[0]); ok {
+			routes[strings.ToUpper(method)+" /"+strings.Trim(path, "/")] = true
+		}
+
+		return true
+	})
+
+	return routes
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+
+	return value, true
+}
@@ -0,0 +1,167 @@
+// ABOUTME: Tests for registering handler routes into the router file.
+// ABOUTME: Verifies route group lookup, handler constructor arguments, imports and idempotency.
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testRoutesTemplate = `postHandler := http.NewPostHandler({{.HandlerArgs}})
+{{.RouterGroup}}.GET("/posts", postHandler.List)
+{{.RouterGroup}}.POST("/posts", postHandler.Create)
+{{.RouterGroup}}.GET("/posts/:id", postHandler.Get)
+{{.RouterGroup}}.PUT("/posts/:id", postHandler.Update)
+{{.RouterGroup}}.DELETE("/posts/:id", postHandler.Delete)`
+
+var testRouterGroup = RouterGroup{Prefix: "/v1", HandlerArgs: "store.S, nil"}
+
+const (
+	testHandlerImport = "example.com/app/internal/apiserver/handler/http"
+	testStoreImport   = "example.com/app/internal/pkg/store"
+)
+
+const testRouter = `package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"example.com/app/internal/apiserver/handler/http/user"
+	"example.com/app/internal/pkg/auth"
+	"example.com/app/internal/pkg/store"
+)
+
+// MapApiRouters registers api routes.
+func MapApiRouters(g *gin.Engine) {
+	authz, _ := auth.NewAuthorizer()
+	api := g.Group("/v1")
+
+	// User
+	userHandler := user.NewUserHandler(store.S, authz)
+	api.GET("/users", userHandler.List)
+}
+`
+
+func TestRegisterRouterGroup(t *testing.T) {
+	got, err := RegisterRouterGroup(testRouter, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("RegisterRouterGroup failed: %v", err)
+	}
+
+	expects := []string{
+		"\t\"example.com/app/internal/apiserver/handler/http\"\n",
+		"\tapi.GET(\"/users\", userHandler.List)\n\n\tpostHandler := http.NewPostHandler(store.S, authz)\n\tapi.GET(\"/posts\", postHandler.List)\n",
+		"\tapi.DELETE(\"/posts/:id\", postHandler.Delete)\n}\n",
+	}
+	for _, expect := range expects {
+		if !strings.Contains(got, expect) {
+			t.Errorf("Expected router to contain %q, got:\n%s", expect, got)
+		}
+	}
+
+	again, err := RegisterRouterGroup(got, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("RegisterRouterGroup on registered file failed: %v", err)
+	}
+	if again != got {
+		t.Errorf("Expected no changes on second run, got:\n%s", again)
+	}
+}
+
+func TestRegisterRouterGroup_GroupBlock(t *testing.T) {
+	content := `package router
+
+import "github.com/gin-gonic/gin"
+
+func MapRoutes(g *gin.Engine) {
+	v1 := g.Group("/v1")
+	{
+		_ = v1
+	}
+
+	g.NoRoute(func(c *gin.Context) {})
+}
+`
+
+	got, err := RegisterRouterGroup(content, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("RegisterRouterGroup failed: %v", err)
+	}
+
+	expects := []string{
+		"\t\t_ = v1\n\n\t\tpostHandler := http.NewPostHandler(store.S, nil)\n",
+		"\t\tv1.DELETE(\"/posts/:id\", postHandler.Delete)\n\t}\n\n\tg.NoRoute",
+		"\t\"example.com/app/internal/pkg/store\"\n",
+	}
+	for _, expect := range expects {
+		if !strings.Contains(got, expect) {
+			t.Errorf("Expected router to contain %q, got:\n%s", expect, got)
+		}
+	}
+}
+
+func TestRegisterRouterGroup_ExistingRoute(t *testing.T) {
+	content := strings.Replace(testRouter, `api.GET("/users", userHandler.List)`, `api.GET("posts/", userHandler.List)`, 1)
+
+	got, err := RegisterRouterGroup(content, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("RegisterRouterGroup failed: %v", err)
+	}
+	if got != content {
+		t.Errorf("Expected no changes when a route exists, got:\n%s", got)
+	}
+}
+
+func TestRegisterRouterGroup_GroupNotFound(t *testing.T) {
+	content := "package router\n\nfunc MapRoutes() {}\n"
+
+	_, err := RegisterRouterGroup(content, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if !errors.Is(err, ErrRouterGroupNotFound) {
+		t.Fatalf("RegisterRouterGroup error = %v, want %v", err, ErrRouterGroupNotFound)
+	}
+
+	// The router of testRouter registers routes on /v1, not /api/v2.
+	group := RouterGroup{Prefix: "api/v2/", HandlerArgs: testRouterGroup.HandlerArgs}
+	if _, err := RegisterRouterGroup(testRouter, testRoutesTemplate, group, testHandlerImport, testStoreImport); !errors.Is(err, ErrRouterGroupNotFound) {
+		t.Errorf("RegisterRouterGroup error = %v, want %v", err, ErrRouterGroupNotFound)
+	}
+}
+
+func TestRegisterRouterGroup_ConfiguredGroup(t *testing.T) {
+	content := "package router\n\nfunc MapRoutes(g *gin.Engine) {\n\tadmin := g.Group(\"/admin/v2\")\n\t_ = admin\n}\n"
+	group := RouterGroup{Prefix: "/admin/v2", HandlerArgs: "store.S"}
+
+	got, err := RegisterRouterGroup(content, testRoutesTemplate, group, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("RegisterRouterGroup failed: %v", err)
+	}
+	for _, want := range []string{`postHandler := http.NewPostHandler(store.S)`, `admin.GET("/posts", postHandler.List)`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in router, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestUnregisterRouterGroup(t *testing.T) {
+	registered, err := RegisterRouterGroup(testRouter, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("RegisterRouterGroup failed: %v", err)
+	}
+
+	got, err := UnregisterRouterGroup(registered, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("UnregisterRouterGroup failed: %v", err)
+	}
+	if got != testRouter {
+		t.Errorf("Expected router restored, got:\n%s", got)
+	}
+
+	again, err := UnregisterRouterGroup(got, testRoutesTemplate, testRouterGroup, testHandlerImport, testStoreImport)
+	if err != nil {
+		t.Fatalf("UnregisterRouterGroup on unregistered file failed: %v", err)
+	}
+	if again != got {
+		t.Errorf("Expected no changes on second run, got:\n%s", again)
+	}
+}
@@ -0,0 +1,448 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+)
+
+// ErrNoSchemaChanges is returned by GenerateMigrationDiff if the database matches the models.
+var ErrNoSchemaChanges = errors.New("nothing to migrate, the database matches the models")
+
+// sizePattern matches the size in a data type, e.g. varchar(255), like gorm does.
+var sizePattern = regexp.MustCompile(`\D*(\d+)\D?`)
+
+// SchemaDiff is the difference between the models and the database, rendered by migration_diff.tpl.
+type SchemaDiff struct {
+	Tables       []*DiffTable
+	StdImports   []string
+	Imports      []string
+	LocalImports []string // Imports of the project, e.g. the model package
+	Up           []string // Migrator calls of Up, e.g. AddColumn(&Post{}, "Body")
+	Down         []string // Migrator calls of Down, reverting Up in reverse order
+}
+
+// DiffTable is a table changed by the migration.
+type DiffTable struct {
+	StructName string
+	Table      string
+	Fields     []string // Field declarations of the columns and indexes Up creates or alters
+	OldFields  []string // Field declarations with the current column types, used by Down to revert altered columns
+}
+
+// tableChange is the difference between a model and its table.
+type tableChange struct {
+	model   *modelStruct
+	create  bool
+	added   []*modelField
+	altered []*modelField
+	old     map[*modelField]gorm.ColumnType // Current columns of altered fields
+	indexes []string
+	indexed map[*modelField]bool
+}
+
+// GenerateMigrationDiff generates a migration which creates the tables, columns and indexes of the
+// models in directory.model missing in the database and alters the columns whose type differs.
+// Columns and indexes which aren't in the models are left alone.
+func (o *Options) GenerateMigrationDiff(path string) error {
+	if err := o.prepare(string(TmplMigration), path); err != nil {
+		return err
+	}
+
+	modelDir := config.Cfg.Directory.Model
+	if o.Service != "" {
+		dir, err := o.InferDirectoryForService(modelDir, o.Service)
+		if err != nil {
+			return fmt.Errorf("failed to infer directory for service %s: %w", o.Service, err)
+		}
+		modelDir = dir
+	}
+
+	models, err := parseModels(modelDir, config.Cfg.RootPackage)
+	if err != nil {
+		return err
+	}
+
+	o.SchemaDiff, err = diffSchema(config.DB, models, o.StructName)
+	if err != nil {
+		return err
+	}
+	o.SchemaDiff.groupImports(config.Cfg.RootPackage)
+	if len(o.SchemaDiff.Tables) == 0 {
+		return ErrNoSchemaChanges
+	}
+
+	codeTemplate, err := ReadTemplate("migration_diff.tpl")
+	if err != nil {
+		return err
+	}
+
+	return o.generateFile(o.FilePath, string(codeTemplate), o.Name, "migration_diff.tpl")
+}
+
+// diffSchema compares models with the database. The struct of a single changed table is named
+// structName, like migration.tpl, otherwise the table name is appended.
+func diffSchema(db *gorm.DB, models []*modelStruct, structName string) (*SchemaDiff, error) {
+	var changes []*tableChange
+	for _, model := range models {
+		change, err := diffModel(db, model)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	diff := &SchemaDiff{}
+	imports := make(map[string]bool)
+	var up, down []string
+
+	// Create the tables first, so other tables' columns may reference them.
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].create && !changes[j].create
+	})
+
+	for _, change := range changes {
+		name := structName
+		if len(changes) > 1 {
+			name += strcase.ToCamel(change.model.Table)
+		}
+
+		table := &DiffTable{StructName: name, Table: change.model.Table}
+		diff.Tables = append(diff.Tables, table)
+		for _, spec := range change.model.Imports {
+			imports[spec] = true
+		}
+
+		if change.create {
+			table.Fields = fieldDeclarations(change.model.Fields, nil)
+			up = append(up, fmt.Sprintf("CreateTable(&%s{})", name))
+			down = append(down, fmt.Sprintf("DropTable(&%s{})", name))
+
+			continue
+		}
+
+		table.Fields = fieldDeclarations(change.model.Fields, func(field *modelField) bool {
+			return change.indexed[field] || slices.Contains(change.added, field) || slices.Contains(change.altered, field)
+		})
+
+		for _, field := range change.added {
+			up = append(up, fmt.Sprintf("AddColumn(&%s{}, %q)", name, field.Name))
+			down = append(down, fmt.Sprintf("DropColumn(&%s{}, %q)", name, field.Name))
+		}
+
+		for _, field := range change.altered {
+			old := *field
+			old.Tag = withColumnType(field.Tag, change.old[field])
+			table.OldFields = append(table.OldFields, old.Source())
+
+			up = append(up, fmt.Sprintf("AlterColumn(&%s{}, %q)", name, field.Name))
+			down = append(down, fmt.Sprintf("AlterColumn(&%sOld{}, %q)", name, field.Name))
+		}
+
+		for _, index := range change.indexes {
+			up = append(up, fmt.Sprintf("CreateIndex(&%s{}, %q)", name, index))
+			down = append(down, fmt.Sprintf("DropIndex(&%s{}, %q)", name, index))
+		}
+	}
+
+	diff.Up = up
+	for i := len(down) - 1; i >= 0; i-- {
+		diff.Down = append(diff.Down, down[i])
+	}
+
+	// The template imports these itself.
+	delete(imports, `"gorm.io/gorm"`)
+	delete(imports, `"github.com/bingo-project/bingoctl/pkg/migrate"`)
+	for spec := range imports {
+		diff.Imports = append(diff.Imports, spec)
+	}
+	sort.Strings(diff.Imports)
+
+	return diff, nil
+}
+
+// groupImports moves the standard library imports and the imports of rootPackage to their own groups.
+func (d *SchemaDiff) groupImports(rootPackage string) {
+	var imports []string
+	for _, spec := range d.Imports {
+		importPath, _ := strconv.Unquote(spec[strings.Index(spec, `"`):])
+		switch {
+		case !strings.Contains(strings.Split(importPath, "/")[0], "."):
+			d.StdImports = append(d.StdImports, spec)
+		case rootPackage != "" && strings.HasPrefix(importPath, rootPackage):
+			d.LocalImports = append(d.LocalImports, spec)
+		default:
+			imports = append(imports, spec)
+		}
+	}
+	d.Imports = imports
+}
+
+// diffModel returns the changes of the table of model, nil if there are none.
+func diffModel(db *gorm.DB, model *modelStruct) (*tableChange, error) {
+	change := &tableChange{
+		model:   model,
+		old:     make(map[*modelField]gorm.ColumnType),
+		indexed: make(map[*modelField]bool),
+	}
+
+	migrator := db.Migrator()
+	if !migrator.HasTable(model.Table) {
+		change.create = true
+
+		return change, nil
+	}
+
+	columnTypes, err := migrator.ColumnTypes(model.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]gorm.ColumnType)
+	for _, column := range columnTypes {
+		columns[strings.ToLower(column.Name())] = column
+	}
+
+	for _, field := range model.Fields {
+		column, ok := columns[strings.ToLower(field.Column)]
+		if !ok {
+			change.added = append(change.added, field)
+			continue
+		}
+
+		if columnChanged(db, field, column) {
+			change.altered = append(change.altered, field)
+			change.old[field] = column
+		}
+	}
+
+	names, fields := modelIndexes(model)
+	for _, name := range names {
+		if migrator.HasIndex(model.Table, name) {
+			continue
+		}
+
+		change.indexes = append(change.indexes, name)
+		for _, field := range fields[name] {
+			change.indexed[field] = true
+		}
+	}
+
+	if len(change.added) == 0 && len(change.altered) == 0 && len(change.indexes) == 0 {
+		return nil, nil
+	}
+
+	return change, nil
+}
+
+// columnChanged reports whether the type or size of the column differs from field, following the checks
+// of gorm's Migrator.MigrateColumn. Columns of unknown Go types and primary keys are never changed.
+func columnChanged(db *gorm.DB, field *modelField, column gorm.ColumnType) bool {
+	f, ok := schemaField(field)
+	if !ok || f.PrimaryKey {
+		return false
+	}
+
+	fullDataType := strings.TrimSpace(strings.ToLower(db.Dialector.DataTypeOf(f)))
+	realDataType := strings.ToLower(column.DatabaseTypeName())
+	if realDataType != "" && !strings.HasPrefix(fullDataType, realDataType) {
+		sameType := false
+		for _, alias := range db.Migrator().GetTypeAliases(realDataType) {
+			if strings.HasPrefix(fullDataType, alias) {
+				sameType = true
+				break
+			}
+		}
+		if !sameType {
+			return true
+		}
+	}
+
+	if length, ok := column.Length(); length != int64(f.Size) {
+		if length > 0 && f.Size > 0 {
+			return true
+		}
+
+		matches := sizePattern.FindAllStringSubmatch(fullDataType, -1)
+		if ok && len(matches) == 1 && matches[0][1] != strconv.FormatInt(length, 10) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// schemaField returns the gorm schema field of field, enough to get its data type from the dialector.
+func schemaField(field *modelField) (*schema.Field, bool) {
+	f := &schema.Field{
+		Name:        field.Name,
+		DBName:      field.Column,
+		TagSettings: field.Settings,
+		PrimaryKey:  strings.EqualFold(field.Column, "id"),
+	}
+	if _, ok := field.Settings["PRIMARYKEY"]; ok {
+		f.PrimaryKey = true
+	}
+	if _, ok := field.Settings["PRIMARY_KEY"]; ok {
+		f.PrimaryKey = true
+	}
+	if _, ok := field.Settings["AUTOINCREMENT"]; ok {
+		f.AutoIncrement = true
+	}
+
+	switch field.Basic {
+	case "string":
+		f.DataType = schema.String
+	case "bool":
+		f.DataType = schema.Bool
+	case "int", "int64", "int32", "int16", "int8":
+		f.DataType, f.Size = schema.Int, bits(field.Basic)
+	case "uint", "uint64", "uint32", "uint16", "uint8":
+		f.DataType, f.Size = schema.Uint, bits(field.Basic)
+	case "float32", "float64":
+		f.DataType, f.Size = schema.Float, bits(field.Basic)
+	case "time.Time":
+		f.DataType = schema.Time
+	case "[]byte":
+		f.DataType = schema.Bytes
+	}
+
+	if typ, ok := field.Settings["TYPE"]; ok {
+		switch dataType := schema.DataType(strings.ToLower(typ)); dataType {
+		case schema.Bool, schema.Int, schema.Uint, schema.Float, schema.String, schema.Time, schema.Bytes:
+			f.DataType = dataType
+		default:
+			f.DataType = schema.DataType(typ)
+		}
+	}
+	if f.DataType == "" {
+		return nil, false
+	}
+
+	if size, err := strconv.Atoi(field.Settings["SIZE"]); err == nil {
+		f.Size = size
+	}
+	if precision, err := strconv.Atoi(field.Settings["PRECISION"]); err == nil {
+		f.Precision = precision
+	}
+	if scale, err := strconv.Atoi(field.Settings["SCALE"]); err == nil {
+		f.Scale = scale
+	}
+
+	return f, true
+}
+
+// bits returns the size of a Go number type.
+func bits(typ string) int {
+	for _, size := range []string{"8", "16", "32", "64"} {
+		if strings.HasSuffix(typ, size) {
+			n, _ := strconv.Atoi(size)
+			return n
+		}
+	}
+
+	return strconv.IntSize
+}
+
+// columnType returns the full type of column, e.g. varchar(255).
+func columnType(column gorm.ColumnType) string {
+	if typ, ok := column.ColumnType(); ok && typ != "" {
+		return typ
+	}
+
+	return column.DatabaseTypeName()
+}
+
+// modelIndexes returns the names of the indexes declared in the gorm tags of model and their fields.
+func modelIndexes(model *modelStruct) ([]string, map[string][]*modelField) {
+	var names []string
+	fields := make(map[string][]*modelField)
+	for _, field := range model.Fields {
+		for _, setting := range strings.Split(reflect.StructTag(field.Tag).Get("gorm"), ";") {
+			key, value, _ := strings.Cut(setting, ":")
+			if key = strings.ToUpper(strings.TrimSpace(key)); key != "INDEX" && key != "UNIQUEINDEX" {
+				continue
+			}
+
+			options := strings.Split(value, ",")
+			name := strings.TrimSpace(options[0])
+			for _, option := range options[1:] {
+				if k, v, _ := strings.Cut(option, ":"); strings.EqualFold(strings.TrimSpace(k), "composite") && name == "" {
+					name = schema.NamingStrategy{}.IndexName(model.Table, strings.TrimSpace(v))
+				}
+			}
+			if name == "" {
+				name = schema.NamingStrategy{}.IndexName(model.Table, field.Column)
+			}
+
+			if _, ok := fields[name]; !ok {
+				names = append(names, name)
+			}
+			fields[name] = append(fields[name], field)
+		}
+	}
+
+	return names, fields
+}
+
+// fieldDeclarations returns the declarations of the fields matching include, all if include is nil.
+// Fields of an embedded struct are declared by embedding it.
+func fieldDeclarations(fields []*modelField, include func(field *modelField) bool) []string {
+	var declarations []string
+	embedded := make(map[string]bool)
+	for _, field := range fields {
+		if include != nil && !include(field) {
+			continue
+		}
+
+		if field.Embed == "" {
+			declarations = append(declarations, field.Source())
+		} else if !embedded[field.Embed] {
+			embedded[field.Embed] = true
+			declarations = append(declarations, field.Embed, "")
+		}
+	}
+
+	// Separate embedded structs from the other fields, like model.tpl does.
+	if len(declarations) > 0 && declarations[len(declarations)-1] == "" {
+		declarations = declarations[:len(declarations)-1]
+	}
+
+	return declarations
+}
+
+// withColumnType returns tag with the gorm type set to the one of column. Size and index settings are dropped.
+func withColumnType(tag string, column gorm.ColumnType) string {
+	value, hasGorm := reflect.StructTag(tag).Lookup("gorm")
+
+	var settings []string
+	for _, setting := range strings.Split(value, ";") {
+		key, _, _ := strings.Cut(setting, ":")
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "", "TYPE", "SIZE", "PRECISION", "SCALE", "INDEX", "UNIQUEINDEX":
+			continue
+		}
+		settings = append(settings, setting)
+	}
+	settings = append(settings, "type:"+columnType(column))
+
+	gormTag := "gorm:" + strconv.Quote(strings.Join(settings, ";"))
+	if !hasGorm {
+		return strings.TrimSpace(tag + " " + gormTag)
+	}
+
+	return strings.Replace(tag, "gorm:"+strconv.Quote(value), gormTag, 1)
+}
@@ -0,0 +1,242 @@
+// ABOUTME: Tests for generating migrations from the difference between the models and the database.
+// ABOUTME: Verifies new tables, columns, altered types and indexes are migrated and reverted.
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+)
+
+const postModel = `package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Status int
+
+type PostM struct {
+	gorm.Model
+
+	Title       string     ` + "`gorm:\"type:varchar(255)\"`" + `
+	Slug        string     ` + "`gorm:\"type:varchar(191);uniqueIndex\"`" + `
+	Status      Status
+	PublishedAt *time.Time
+	Author      *UserM     ` + "`gorm:\"foreignKey:AuthorID\"`" + `
+	AuthorID    uint
+	Comments    []CommentM
+	Ignored     string     ` + "`gorm:\"-\"`" + `
+}
+
+func (*PostM) TableName() string {
+	return "posts"
+}
+
+type UserM struct {
+	gorm.Model
+
+	Name string
+}
+
+type CommentM struct {
+	Body string
+}
+`
+
+// setupDiffProject writes the post model to a temp project and returns a database
+// with a posts table missing some of its columns.
+func setupDiffProject(t *testing.T) *gorm.DB {
+	setupTemplateDirs(t)
+	config.Cfg.RootPackage = "example.com/app"
+	config.Cfg.Directory.Model = "internal/model"
+	config.Cfg.Directory.Migration = "internal/migration"
+
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	os.Chdir(dir)
+
+	if err := os.MkdirAll("internal/model", 0755); err != nil {
+		t.Fatalf("Failed to create model dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("internal/model", "post.go"), []byte(postModel), 0644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+
+	type Post struct {
+		ID        uint
+		Title     string `gorm:"type:varchar(100)"`
+		CreatedAt time.Time
+	}
+	if err := db.Migrator().CreateTable(&Post{}); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	original := config.DB
+	config.DB = db
+	t.Cleanup(func() { config.DB = original })
+
+	return db
+}
+
+func TestParseModels(t *testing.T) {
+	setupDiffProject(t)
+
+	models, err := parseModels("internal/model", "example.com/app")
+	if err != nil {
+		t.Fatalf("parseModels failed: %v", err)
+	}
+
+	if len(models) != 2 || models[0].Table != "posts" || models[1].Table != "user_ms" {
+		t.Fatalf("Unexpected models: %+v", models)
+	}
+
+	var columns []string
+	for _, field := range models[0].Fields {
+		columns = append(columns, field.Column+" "+field.Type)
+	}
+
+	want := []string{
+		"id uint", "created_at time.Time", "updated_at time.Time", "deleted_at gorm.DeletedAt",
+		"title string", "slug string", "status model.Status", "published_at *time.Time", "author_id uint",
+	}
+	if strings.Join(columns, ",") != strings.Join(want, ",") {
+		t.Errorf("Columns = %v, want %v", columns, want)
+	}
+}
+
+func TestParseModelPackage_SeveralPackages(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "model")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	files := map[string]string{
+		"gen.go":  "//go:build ignore\n\npackage main\n\ntype Generator struct{}\n",
+		"post.go": "package model\n\ntype Post struct{ ID uint }\n\nfunc (Post) TableName() string { return \"posts\" }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		pkg, err := parseModelPackage(dir, "example.com/app/model")
+		if err != nil {
+			t.Fatalf("parseModelPackage failed: %v", err)
+		}
+		if pkg.name != "model" || pkg.types["Post"] == nil {
+			t.Fatalf("Expected the model package, got %s", pkg.name)
+		}
+	}
+
+	// No package named after the directory
+	other := filepath.Join(filepath.Dir(dir), "other")
+	if err := os.Rename(dir, other); err != nil {
+		t.Fatalf("Failed to rename dir: %v", err)
+	}
+	if _, err := parseModelPackage(other, "example.com/app/other"); err == nil || !strings.Contains(err.Error(), "main, model") {
+		t.Errorf("Expected error listing the packages, got %v", err)
+	}
+}
+
+func TestGenerateMigrationDiff(t *testing.T) {
+	setupDiffProject(t)
+
+	o := &Options{}
+	if err := o.GenerateMigrationDiff("add_post_fields"); err != nil {
+		t.Fatalf("GenerateMigrationDiff failed: %v", err)
+	}
+
+	content, err := os.ReadFile(o.FilePath)
+	if err != nil {
+		t.Fatalf("Failed to read migration: %v", err)
+	}
+	code := string(content)
+
+	for _, want := range []string{
+		`"example.com/app/internal/model"`,
+		"type AddPostFieldsPosts struct {\n\tgorm.Model\n\n\tTitle ",
+		"Status      model.Status",
+		"type AddPostFieldsPostsOld struct {\n\tTitle string `gorm:\"type:varchar(100)\"`\n}",
+		`migrator.CreateTable(&AddPostFieldsUserMs{})`,
+		`migrator.AddColumn(&AddPostFieldsPosts{}, "Slug")`,
+		`migrator.AlterColumn(&AddPostFieldsPosts{}, "Title")`,
+		`migrator.CreateIndex(&AddPostFieldsPosts{}, "idx_posts_slug")`,
+		`migrator.DropIndex(&AddPostFieldsPosts{}, "idx_posts_slug")`,
+		`migrator.AlterColumn(&AddPostFieldsPostsOld{}, "Title")`,
+		`migrator.DropColumn(&AddPostFieldsPosts{}, "Slug")`,
+		`migrator.DropTable(&AddPostFieldsUserMs{})`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected migration to contain %q, got:\n%s", want, code)
+		}
+	}
+
+	// Existing columns aren't added, associations and ignored fields aren't columns.
+	for _, unwanted := range []string{`"CreatedAt")`, `"Author")`, `"Comments")`, `"Ignored")`} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("Expected migration not to contain %q", unwanted)
+		}
+	}
+
+	// Down reverts Up in reverse order.
+	up := strings.Index(code, `CreateIndex(&AddPostFieldsPosts{}, "idx_posts_slug")`)
+	add := strings.Index(code, `AddColumn(&AddPostFieldsPosts{}, "Slug")`)
+	drop := strings.Index(code, `DropColumn(&AddPostFieldsPosts{}, "Slug")`)
+	dropIndex := strings.Index(code, `DropIndex(&AddPostFieldsPosts{}, "idx_posts_slug")`)
+	if add > up || dropIndex > drop {
+		t.Errorf("Unexpected order of migrator calls:\n%s", code)
+	}
+}
+
+func TestGenerateMigrationDiff_NoChanges(t *testing.T) {
+	db := setupDiffProject(t)
+
+	type Status int
+	type Post struct {
+		gorm.Model
+
+		Title       string `gorm:"type:varchar(255)"`
+		Slug        string `gorm:"type:varchar(191);uniqueIndex"`
+		Status      Status
+		PublishedAt *time.Time
+		AuthorID    uint
+	}
+	type UserM struct {
+		gorm.Model
+
+		Name string
+	}
+	if err := db.Migrator().DropTable("posts"); err != nil {
+		t.Fatalf("Failed to drop table: %v", err)
+	}
+	if err := db.AutoMigrate(&Post{}); err != nil {
+		t.Fatalf("Failed to migrate posts: %v", err)
+	}
+	if err := db.AutoMigrate(&UserM{}); err != nil {
+		t.Fatalf("Failed to migrate users: %v", err)
+	}
+
+	err := (&Options{}).GenerateMigrationDiff("nothing")
+	if !errors.Is(err, ErrNoSchemaChanges) {
+		t.Fatalf("GenerateMigrationDiff error = %v, want %v", err, ErrNoSchemaChanges)
+	}
+}
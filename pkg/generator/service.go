@@ -1,13 +1,14 @@
 package generator
 
 import (
+	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/bingo-project/bingoctl/pkg/config"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
 )
 
 // getAppName extracts the application name from the root package.
@@ -96,6 +97,10 @@ func (o *Options) GenerateService(name string) error {
 		return err
 	}
 
+	if cmdutil.DryRun {
+		return nil
+	}
+
 	appName := getAppName()
 	cmdDirName := appName + "-" + o.ServiceName
 	fmt.Printf("Service '%s' generated successfully!\n", o.ServiceName)
@@ -106,89 +111,53 @@ func (o *Options) GenerateService(name string) error {
 	return nil
 }
 
-func (o *Options) generateCmdMain() error {
-	tplContent, err := ReadServiceTemplate("cmd_main.go.tpl")
+// renderServiceTemplate renders a service template and writes the result to filePath.
+func (o *Options) renderServiceTemplate(tplName, filePath string, data any) error {
+	tplContent, err := ReadServiceTemplate(tplName)
 	if err != nil {
 		return err
 	}
 
-	tmpl, err := template.New("cmd_main").Parse(string(tplContent))
+	tmpl, err := template.New(tplName).Parse(string(tplContent))
 	if err != nil {
 		return err
 	}
 
-	// cmd directory uses app-service format, e.g., demo-admin
-	appName := getAppName()
-	cmdDirName := appName + "-" + o.ServiceName
-	cmdDir := filepath.Join("cmd", cmdDirName)
-	if err := os.MkdirAll(cmdDir, 0755); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
 		return err
 	}
 
-	filePath := filepath.Join(cmdDir, "main.go")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	return o.writeGeneratedFile(filePath, buf.Bytes(), false, "service/"+tplName)
+}
+
+func (o *Options) generateCmdMain() error {
+	// cmd directory uses app-service format, e.g., demo-admin
+	appName := getAppName()
+	cmdDirName := appName + "-" + o.ServiceName
+	filePath := filepath.Join("cmd", cmdDirName, "main.go")
 
 	data := map[string]string{
 		"RootPackage": config.Cfg.RootPackage,
 		"ServiceName": o.ServiceName,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate("cmd_main.go.tpl", filePath, data)
 }
 
 func (o *Options) generateApp() error {
-	tplContent, err := ReadServiceTemplate("app.go.tpl")
-	if err != nil {
-		return err
-	}
-
-	tmpl, err := template.New("app").Parse(string(tplContent))
-	if err != nil {
-		return err
-	}
-
-	internalDir := filepath.Join("internal", o.ServiceName)
-	if err := os.MkdirAll(internalDir, 0755); err != nil {
-		return err
-	}
-
-	filePath := filepath.Join(internalDir, "app.go")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	filePath := filepath.Join("internal", o.ServiceName, "app.go")
 
 	data := map[string]string{
 		"RootPackage": config.Cfg.RootPackage,
 		"ServiceName": o.ServiceName,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate("app.go.tpl", filePath, data)
 }
 
 func (o *Options) generateRun() error {
-	tplContent, err := ReadServiceTemplate("run.go.tpl")
-	if err != nil {
-		return err
-	}
-
-	tmpl, err := template.New("run").Parse(string(tplContent))
-	if err != nil {
-		return err
-	}
-
-	internalDir := filepath.Join("internal", o.ServiceName)
-	filePath := filepath.Join(internalDir, "run.go")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	filePath := filepath.Join("internal", o.ServiceName, "run.go")
 
 	data := map[string]any{
 		"RootPackage": config.Cfg.RootPackage,
@@ -198,88 +167,40 @@ func (o *Options) generateRun() error {
 		"EnableWS":    o.EnableWS,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate("run.go.tpl", filePath, data)
 }
 
 func (o *Options) generateHTTP() error {
-	tplContent, err := ReadServiceTemplate("http.go.tpl")
-	if err != nil {
-		return err
-	}
-
-	tmpl, err := template.New("http").Parse(string(tplContent))
-	if err != nil {
-		return err
-	}
-
-	internalDir := filepath.Join("internal", o.ServiceName)
-	filePath := filepath.Join(internalDir, "http.go")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	filePath := filepath.Join("internal", o.ServiceName, "http.go")
 
 	data := map[string]string{
 		"RootPackage": config.Cfg.RootPackage,
 		"ServiceName": o.ServiceName,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate("http.go.tpl", filePath, data)
 }
 
 func (o *Options) generateGRPCServer() error {
-	tplContent, err := ReadServiceTemplate("grpc.go.tpl")
-	if err != nil {
-		return err
-	}
-
-	tmpl, err := template.New("grpc").Parse(string(tplContent))
-	if err != nil {
-		return err
-	}
-
-	internalDir := filepath.Join("internal", o.ServiceName)
-	filePath := filepath.Join(internalDir, "grpc.go")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	filePath := filepath.Join("internal", o.ServiceName, "grpc.go")
 
 	data := map[string]string{
 		"RootPackage": config.Cfg.RootPackage,
 		"ServiceName": o.ServiceName,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate("grpc.go.tpl", filePath, data)
 }
 
 func (o *Options) generateWS() error {
-	tplContent, err := ReadServiceTemplate("ws.go.tpl")
-	if err != nil {
-		return err
-	}
-
-	tmpl, err := template.New("ws").Parse(string(tplContent))
-	if err != nil {
-		return err
-	}
-
-	internalDir := filepath.Join("internal", o.ServiceName)
-	filePath := filepath.Join(internalDir, "ws.go")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	filePath := filepath.Join("internal", o.ServiceName, "ws.go")
 
 	data := map[string]string{
 		"RootPackage": config.Cfg.RootPackage,
 		"ServiceName": o.ServiceName,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate("ws.go.tpl", filePath, data)
 }
 
 func (o *Options) generateHandler() error {
@@ -288,89 +209,28 @@ func (o *Options) generateHandler() error {
 		"ServiceName": o.ServiceName,
 	}
 
+	handlerDir := filepath.Join("internal", o.ServiceName, "handler")
+
 	// Generate HTTP handler if HTTP is enabled
 	if o.EnableHTTP {
-		handlerDir := filepath.Join("internal", o.ServiceName, "handler", "http")
-		if err := os.MkdirAll(handlerDir, 0755); err != nil {
-			return err
-		}
-
-		tplContent, err := ReadServiceTemplate("handler_http.go.tpl")
-		if err != nil {
-			return err
-		}
-
-		tmpl, err := template.New("handler_http").Parse(string(tplContent))
-		if err != nil {
-			return err
-		}
-
-		filePath := filepath.Join(handlerDir, "handler.go")
-		file, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		if err := tmpl.Execute(file, data); err != nil {
+		filePath := filepath.Join(handlerDir, "http", "handler.go")
+		if err := o.renderServiceTemplate("handler_http.go.tpl", filePath, data); err != nil {
 			return err
 		}
 	}
 
 	// Generate gRPC handler if gRPC is enabled
 	if o.EnableGRPC {
-		handlerDir := filepath.Join("internal", o.ServiceName, "handler", "grpc")
-		if err := os.MkdirAll(handlerDir, 0755); err != nil {
-			return err
-		}
-
-		tplContent, err := ReadServiceTemplate("handler_grpc.go.tpl")
-		if err != nil {
-			return err
-		}
-
-		tmpl, err := template.New("handler_grpc").Parse(string(tplContent))
-		if err != nil {
-			return err
-		}
-
-		filePath := filepath.Join(handlerDir, "handler.go")
-		file, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		if err := tmpl.Execute(file, data); err != nil {
+		filePath := filepath.Join(handlerDir, "grpc", "handler.go")
+		if err := o.renderServiceTemplate("handler_grpc.go.tpl", filePath, data); err != nil {
 			return err
 		}
 	}
 
 	// Generate WebSocket handler if WebSocket is enabled
 	if o.EnableWS {
-		handlerDir := filepath.Join("internal", o.ServiceName, "handler", "ws")
-		if err := os.MkdirAll(handlerDir, 0755); err != nil {
-			return err
-		}
-
-		tplContent, err := ReadServiceTemplate("handler_ws.go.tpl")
-		if err != nil {
-			return err
-		}
-
-		tmpl, err := template.New("handler_ws").Parse(string(tplContent))
-		if err != nil {
-			return err
-		}
-
-		filePath := filepath.Join(handlerDir, "handler.go")
-		file, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		if err := tmpl.Execute(file, data); err != nil {
+		filePath := filepath.Join(handlerDir, "ws", "handler.go")
+		if err := o.renderServiceTemplate("handler_ws.go.tpl", filePath, data); err != nil {
 			return err
 		}
 	}
@@ -380,9 +240,6 @@ func (o *Options) generateHandler() error {
 
 func (o *Options) generateRouter() error {
 	routerDir := filepath.Join("internal", o.ServiceName, "router")
-	if err := os.MkdirAll(routerDir, 0755); err != nil {
-		return err
-	}
 
 	data := map[string]string{
 		"RootPackage": config.Cfg.RootPackage,
@@ -391,72 +248,21 @@ func (o *Options) generateRouter() error {
 
 	// Generate HTTP router if HTTP is enabled
 	if o.EnableHTTP {
-		tplContent, err := ReadServiceTemplate("router_http.go.tpl")
-		if err != nil {
-			return err
-		}
-
-		tmpl, err := template.New("router_http").Parse(string(tplContent))
-		if err != nil {
-			return err
-		}
-
-		filePath := filepath.Join(routerDir, "http.go")
-		file, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		if err := tmpl.Execute(file, data); err != nil {
+		if err := o.renderServiceTemplate("router_http.go.tpl", filepath.Join(routerDir, "http.go"), data); err != nil {
 			return err
 		}
 	}
 
 	// Generate gRPC router if gRPC is enabled
 	if o.EnableGRPC {
-		tplContent, err := ReadServiceTemplate("router_grpc.go.tpl")
-		if err != nil {
-			return err
-		}
-
-		tmpl, err := template.New("router_grpc").Parse(string(tplContent))
-		if err != nil {
-			return err
-		}
-
-		filePath := filepath.Join(routerDir, "grpc.go")
-		file, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		if err := tmpl.Execute(file, data); err != nil {
+		if err := o.renderServiceTemplate("router_grpc.go.tpl", filepath.Join(routerDir, "grpc.go"), data); err != nil {
 			return err
 		}
 	}
 
 	// Generate WebSocket router if WebSocket is enabled
 	if o.EnableWS {
-		tplContent, err := ReadServiceTemplate("router_ws.go.tpl")
-		if err != nil {
-			return err
-		}
-
-		tmpl, err := template.New("router_ws").Parse(string(tplContent))
-		if err != nil {
-			return err
-		}
-
-		filePath := filepath.Join(routerDir, "ws.go")
-		file, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		if err := tmpl.Execute(file, data); err != nil {
+		if err := o.renderServiceTemplate("router_ws.go.tpl", filepath.Join(routerDir, "ws.go"), data); err != nil {
 			return err
 		}
 	}
@@ -465,27 +271,7 @@ func (o *Options) generateRouter() error {
 }
 
 func (o *Options) generateConfig() error {
-	tplContent, err := ReadServiceTemplate("config.yaml.tpl")
-	if err != nil {
-		return err
-	}
-
-	tmpl, err := template.New("config").Parse(string(tplContent))
-	if err != nil {
-		return err
-	}
-
-	configsDir := "configs"
-	if err := os.MkdirAll(configsDir, 0755); err != nil {
-		return err
-	}
-
-	filePath := filepath.Join(configsDir, o.ServiceName+".yaml")
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	filePath := filepath.Join("configs", o.ServiceName+".yaml")
 
 	data := map[string]bool{
 		"EnableHTTP": o.EnableHTTP,
@@ -493,32 +279,19 @@ func (o *Options) generateConfig() error {
 		"EnableWS":   o.EnableWS,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate("config.yaml.tpl", filePath, data)
 }
 
 func (o *Options) createDirectory(parts ...string) error {
-	dir := filepath.Join(parts...)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-
 	// Create .gitkeep file
-	gitkeepPath := filepath.Join(dir, ".gitkeep")
-	file, err := os.Create(gitkeepPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	gitkeepPath := filepath.Join(filepath.Join(parts...), ".gitkeep")
 
-	return nil
+	return cmdutil.WriteFile(gitkeepPath, nil)
 }
 
 // createDirectoryWithFile creates a directory and a basic file for specific directories.
 func (o *Options) createDirectoryWithFile(dirType string, parts ...string) error {
 	dir := filepath.Join(parts...)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
 
 	// Create a basic file based on directory type
 	var fileName, tplName string
@@ -531,39 +304,13 @@ func (o *Options) createDirectoryWithFile(dirType string, parts ...string) error
 		tplName = "handler.go.tpl"
 	default:
 		// For other directories, just create .gitkeep
-		gitkeepPath := filepath.Join(dir, ".gitkeep")
-		file, err := os.Create(gitkeepPath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-		return nil
-	}
-
-	// Read template
-	tplContent, err := ReadServiceTemplate(tplName)
-	if err != nil {
-		return err
-	}
-
-	tmpl, err := template.New(tplName).Parse(string(tplContent))
-	if err != nil {
-		return err
-	}
-
-	// Create file
-	filePath := filepath.Join(dir, fileName)
-	file, err := os.Create(filePath)
-	if err != nil {
-		return err
+		return o.createDirectory(parts...)
 	}
-	defer file.Close()
 
-	// Execute template
 	data := map[string]string{
 		"RootPackage": config.Cfg.RootPackage,
 		"ServiceName": o.ServiceName,
 	}
 
-	return tmpl.Execute(file, data)
+	return o.renderServiceTemplate(tplName, filepath.Join(dir, fileName), data)
 }
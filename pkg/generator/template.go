@@ -3,6 +3,16 @@ package generator
 import (
 	"embed"
 	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mgutz/ansi"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
 )
 
 type Tmpl string
@@ -23,30 +33,134 @@ const (
 	TmplJob        Tmpl = "job"
 	TmplMigration  Tmpl = "migration"
 	TmplSeeder     Tmpl = "seeder"
+	TmplFactory    Tmpl = "factory"
 	TmplService    Tmpl = "service"
 )
 
+// TemplateDirs returns the directories searched for template overrides, in lookup order:
+// the project template directory from .bingo.yaml, then ~/.bingo/templates/make.
+func TemplateDirs() []string {
+	var dirs []string
+	if config.Cfg != nil {
+		dirs = append(dirs, config.Cfg.GetTemplateDirectory())
+	}
+
+	if dir, err := UserTemplateDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
+// UserTemplateDir returns the user-level template directory.
+func UserTemplateDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".bingo", "templates", "make"), nil
+}
+
+// ReadTemplate reads a template by its path relative to tpl/, e.g. "handler.tpl" or "service/app.go.tpl".
+// Overrides in TemplateDirs take precedence over the embedded defaults.
+func ReadTemplate(name string) ([]byte, error) {
+	for _, dir := range TemplateDirs() {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err == nil {
+			return content, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return tplFS.ReadFile(path.Join("tpl", name))
+}
+
 // ReadServiceTemplate reads a service template file by name.
 func ReadServiceTemplate(name string) ([]byte, error) {
-	return tplFS.ReadFile(fmt.Sprintf("tpl/service/%s", name))
+	return ReadTemplate(fmt.Sprintf("service/%s", name))
 }
 
 func (o *Options) ReadCodeTemplates() *Options {
 	// Read template
-	codeTemplateBytes, _ := tplFS.ReadFile(fmt.Sprintf("tpl/%s.tpl", o.Name))
+	codeTemplateBytes, _ := ReadTemplate(fmt.Sprintf("%s.tpl", o.Name))
 	o.CodeTemplate = string(codeTemplateBytes)
 
 	// Read interface template
-	interfaceTemplateBytes, _ := tplFS.ReadFile(fmt.Sprintf("tpl/%s_interface.tpl", o.Name))
+	interfaceTemplateBytes, _ := ReadTemplate(fmt.Sprintf("%s_interface.tpl", o.Name))
 	o.InterfaceTemplate = string(interfaceTemplateBytes)
 
 	// Ream registry template
-	registerTemplateBytes, _ := tplFS.ReadFile(fmt.Sprintf("tpl/%s_registry.tpl", o.Name))
+	registerTemplateBytes, _ := ReadTemplate(fmt.Sprintf("%s_registry.tpl", o.Name))
 	o.RegisterTemplate = string(registerTemplateBytes)
 
+	// Read test template
+	testTemplateBytes, _ := ReadTemplate(fmt.Sprintf("%s_test.tpl", o.Name))
+	o.TestTemplate = string(testTemplateBytes)
+
 	// Read field template
-	fieldTemplateBytes, _ := tplFS.ReadFile(fmt.Sprintf("tpl/%s_field.tpl", o.Name))
+	fieldTemplateBytes, _ := ReadTemplate(fmt.Sprintf("%s_field.tpl", o.Name))
 	o.FieldTemplate = string(fieldTemplateBytes)
 
 	return o
 }
+
+// PublishTemplates copies the embedded templates to dir for editing.
+// If names are given, only templates of these generators are published, e.g. "handler" publishes
+// handler.tpl and handler_*.tpl, "service" publishes service/*.
+// Existing files are skipped unless force is set.
+func PublishTemplates(dir string, force bool, names ...string) ([]string, error) {
+	var published []string
+
+	err := fs.WalkDir(tplFS, "tpl", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		name := strings.TrimPrefix(p, "tpl/")
+		if len(names) > 0 && !matchTemplateName(name, names) {
+			return nil
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if _, err := os.Stat(target); err == nil && !force {
+			fmt.Printf("%s %s\n", ansi.Color("Skipped:", "yellow"), target)
+
+			return nil
+		}
+
+		content, err := tplFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		if err := cmdutil.WriteFile(target, content); err != nil {
+			return err
+		}
+
+		if !cmdutil.DryRun {
+			fmt.Printf("%s %s\n", ansi.Color("Published:", "green"), target)
+		}
+		published = append(published, target)
+
+		return nil
+	})
+
+	return published, err
+}
+
+func matchTemplateName(name string, names []string) bool {
+	base := strings.TrimSuffix(name, ".tpl")
+	for _, n := range names {
+		if base == n || strings.HasPrefix(base, n+"_") || strings.HasPrefix(name, n+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,148 @@
+// ABOUTME: Tests for template lookup and publishing.
+// ABOUTME: Verifies project and user overrides take precedence over embedded templates.
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bingo-project/bingoctl/pkg/config"
+	cmdutil "github.com/bingo-project/bingoctl/pkg/util"
+)
+
+func setupTemplateDirs(t *testing.T) (projectDir, userDir string) {
+	root := t.TempDir()
+
+	// Keep the go command run by goimports from writing its config, e.g. telemetry counters, to the
+	// temp home after the test is done.
+	if dir, err := os.UserConfigDir(); err == nil {
+		t.Setenv("XDG_CONFIG_HOME", dir)
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		t.Setenv("XDG_CACHE_HOME", dir)
+	}
+	t.Setenv("HOME", filepath.Join(root, "home"))
+
+	cfg := config.NewDefaultConfig()
+	cfg.Template.Directory = filepath.Join(root, "project")
+
+	original := config.Cfg
+	config.Cfg = cfg
+	t.Cleanup(func() { config.Cfg = original })
+
+	userDir, err := UserTemplateDir()
+	if err != nil {
+		t.Fatalf("UserTemplateDir failed: %v", err)
+	}
+
+	return cfg.Template.Directory, userDir
+}
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+}
+
+func TestReadTemplate_LookupChain(t *testing.T) {
+	projectDir, userDir := setupTemplateDirs(t)
+
+	embedded, err := tplFS.ReadFile("tpl/biz.tpl")
+	if err != nil {
+		t.Fatalf("Failed to read embedded template: %v", err)
+	}
+
+	// Embedded default
+	content, err := ReadTemplate("biz.tpl")
+	if err != nil {
+		t.Fatalf("ReadTemplate failed: %v", err)
+	}
+	if string(content) != string(embedded) {
+		t.Error("Expected embedded template without overrides")
+	}
+
+	// User override
+	writeTemplate(t, userDir, "biz.tpl", "user")
+	content, _ = ReadTemplate("biz.tpl")
+	if string(content) != "user" {
+		t.Errorf("Expected user override, got %q", content)
+	}
+
+	// Project override wins
+	writeTemplate(t, projectDir, "biz.tpl", "project")
+	content, _ = ReadTemplate("biz.tpl")
+	if string(content) != "project" {
+		t.Errorf("Expected project override, got %q", content)
+	}
+
+	// Service templates and template variants
+	writeTemplate(t, projectDir, "service/app.go.tpl", "app")
+	writeTemplate(t, userDir, "store_registry.tpl", "registry")
+
+	content, _ = ReadServiceTemplate("app.go.tpl")
+	if string(content) != "app" {
+		t.Errorf("Expected service override, got %q", content)
+	}
+
+	o := &Options{Name: string(TmplStore)}
+	o.ReadCodeTemplates()
+	if o.RegisterTemplate != "registry" {
+		t.Errorf("Expected registry override, got %q", o.RegisterTemplate)
+	}
+	if o.InterfaceTemplate == "" {
+		t.Error("Expected embedded interface template")
+	}
+}
+
+func TestPublishTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	published, err := PublishTemplates(dir, false, "store", "service")
+	if err != nil {
+		t.Fatalf("PublishTemplates failed: %v", err)
+	}
+
+	for _, name := range []string{"store.tpl", "store_field.tpl", "store_interface.tpl", "store_registry.tpl", "service/app.go.tpl"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be published: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "biz.tpl")); !os.IsNotExist(err) {
+		t.Error("biz.tpl should not be published")
+	}
+
+	// Existing files are kept unless force
+	writeTemplate(t, dir, "store.tpl", "custom")
+	again, err := PublishTemplates(dir, false, "store")
+	if err != nil {
+		t.Fatalf("PublishTemplates failed: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("Expected no template published again, got %v", again)
+	}
+
+	forced, err := PublishTemplates(dir, true, "store")
+	if err != nil {
+		t.Fatalf("PublishTemplates failed: %v", err)
+	}
+	if len(forced) != 5 || len(published) < 5 {
+		t.Errorf("Expected 5 templates published with force, got %v", forced)
+	}
+
+	// Nothing is written in dry-run mode
+	cmdutil.DryRun = true
+	defer func() { cmdutil.DryRun = false }()
+	dryRunDir := t.TempDir()
+	previewed, err := PublishTemplates(dryRunDir, false, "store")
+	if err != nil {
+		t.Fatalf("PublishTemplates failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dryRunDir, "store.tpl")); len(previewed) != 5 || !os.IsNotExist(err) {
+		t.Errorf("Expected 5 templates previewed and none written, got %v", previewed)
+	}
+}
@@ -1,3 +1,3 @@
 	if req.{{.Name}} != nil {
-    	{{.VariableName}}M.{{.Name}} = *req.{{.Name}}
+    	{{.VariableName}}M.{{.Name}} = {{.Deref}}req.{{.Name}}
 	}
\ No newline at end of file
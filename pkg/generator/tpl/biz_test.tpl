@@ -0,0 +1,135 @@
+package {{.PackageName}}
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"{{.RootPackage}}/{{.StorePath}}"
+	model "{{.RootPackage}}/{{.ModelPath}}{{.RelativePath}}"
+	v1 "{{.RootPackage}}/{{.RequestPath}}{{.RelativePath}}"
+	"{{.RootPackage}}/pkg/store/where"
+)
+
+// fake{{.StructName}}DataStore mocks store.IStore, methods other than {{.StructName}}() are not implemented.
+type fake{{.StructName}}DataStore struct {
+	store.IStore
+
+	{{.VariableName}} *fake{{.StructName}}Store
+}
+
+func (ds *fake{{.StructName}}DataStore) {{.StructName}}() store.{{.StructName}}Store {
+	return ds.{{.VariableName}}
+}
+
+// fake{{.StructName}}Store mocks store.{{.StructName}}Store with a single record in memory.
+type fake{{.StructName}}Store struct {
+	store.{{.StructName}}Store
+
+	item *model.{{.StructName}}M
+}
+
+func (s *fake{{.StructName}}Store) Create(ctx context.Context, obj *model.{{.StructName}}M) error {
+	obj.ID = 1
+	s.item = obj
+
+	return nil
+}
+
+func (s *fake{{.StructName}}Store) Update(ctx context.Context, obj *model.{{.StructName}}M, fields ...string) error {
+	s.item = obj
+
+	return nil
+}
+
+func (s *fake{{.StructName}}Store) Delete(ctx context.Context, opts *where.Options) error {
+	s.item = nil
+
+	return nil
+}
+
+func (s *fake{{.StructName}}Store) Get(ctx context.Context, opts *where.Options) (*model.{{.StructName}}M, error) {
+	if s.item == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return s.item, nil
+}
+
+func (s *fake{{.StructName}}Store) ListWithRequest(ctx context.Context, req *v1.List{{.StructName}}Request) (int64, []*model.{{.StructName}}M, error) {
+	if s.item == nil {
+		return 0, nil, nil
+	}
+
+	return 1, []*model.{{.StructName}}M{s.item}, nil
+}
+
+func newTest{{.StructName}}Biz() (*{{.VariableName}}Biz, *fake{{.StructName}}Store) {
+	s := &fake{{.StructName}}Store{}
+
+	return New{{.StructName}}(&fake{{.StructName}}DataStore{ {{- .VariableName}}: s}), s
+}
+
+func Test{{.StructName}}Biz_Create(t *testing.T) {
+	b, s := newTest{{.StructName}}Biz()
+
+	resp, err := b.Create(context.Background(), &v1.Create{{.StructName}}Request{})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if resp == nil || s.item == nil {
+		t.Fatal("Expected {{.VariableName}} created")
+	}
+}
+
+func Test{{.StructName}}Biz_Get(t *testing.T) {
+	b, s := newTest{{.StructName}}Biz()
+
+	if _, err := b.Get(context.Background(), 1); err == nil {
+		t.Fatal("Expected error for missing {{.VariableName}}, got nil")
+	}
+
+	s.item = &model.{{.StructName}}M{}
+	if _, err := b.Get(context.Background(), 1); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+}
+
+func Test{{.StructName}}Biz_List(t *testing.T) {
+	b, s := newTest{{.StructName}}Biz()
+	s.item = &model.{{.StructName}}M{}
+
+	resp, err := b.List(context.Background(), &v1.List{{.StructName}}Request{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if resp.Total != 1 || len(resp.Data) != 1 {
+		t.Errorf("Expected 1 {{.VariableName}}, got total %d, data %d", resp.Total, len(resp.Data))
+	}
+}
+
+func Test{{.StructName}}Biz_Update(t *testing.T) {
+	b, s := newTest{{.StructName}}Biz()
+
+	if _, err := b.Update(context.Background(), 1, &v1.Update{{.StructName}}Request{}); err == nil {
+		t.Fatal("Expected error for missing {{.VariableName}}, got nil")
+	}
+
+	s.item = &model.{{.StructName}}M{}
+	if _, err := b.Update(context.Background(), 1, &v1.Update{{.StructName}}Request{}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+}
+
+func Test{{.StructName}}Biz_Delete(t *testing.T) {
+	b, s := newTest{{.StructName}}Biz()
+	s.item = &model.{{.StructName}}M{}
+
+	if err := b.Delete(context.Background(), 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if s.item != nil {
+		t.Error("Expected {{.VariableName}} deleted")
+	}
+}
@@ -0,0 +1,23 @@
+package {{.PackageName}}
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/bingo-project/bingoctl/pkg/fake"
+
+	model "{{.RootPackage}}/{{.ModelPath}}{{.RelativePath}}"
+)
+
+// {{.StructName}}Factory makes model.{{.FactoryModel}} with fake values, e.g. in a seeder:
+//
+//	{{.VariableNamePlural}}, err := {{.PackageName}}.{{.StructName}}Factory(db).Count(10).Create()
+func {{.StructName}}Factory(db *gorm.DB) *fake.Factory[model.{{.FactoryModel}}] {
+	return fake.New(db, func(f *fake.Faker) model.{{.FactoryModel}} {
+		var m model.{{.FactoryModel}}
+{{- range .FactoryFields}}
+		m.{{.Name}} = {{.Value}}
+{{- end}}
+
+		return m
+	})
+}
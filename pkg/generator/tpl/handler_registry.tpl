@@ -0,0 +1,6 @@
+{{.VariableName}}Handler := {{.Package}}New{{.StructName}}Handler({{.HandlerArgs}})
+{{.RouterGroup}}.GET("/{{.VariableNamePlural}}", {{.VariableName}}Handler.List)
+{{.RouterGroup}}.POST("/{{.VariableNamePlural}}", {{.VariableName}}Handler.Create)
+{{.RouterGroup}}.GET("/{{.VariableNamePlural}}/:id", {{.VariableName}}Handler.Get)
+{{.RouterGroup}}.PUT("/{{.VariableNamePlural}}/:id", {{.VariableName}}Handler.Update)
+{{.RouterGroup}}.DELETE("/{{.VariableNamePlural}}/:id", {{.VariableName}}Handler.Delete)
\ No newline at end of file
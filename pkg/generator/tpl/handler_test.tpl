@@ -0,0 +1,113 @@
+package {{.PackageName}}
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"{{.RootPackage}}/{{.BizPath}}"
+{{- if .RelativePath}}
+	{{.VariableName}}biz "{{.RootPackage}}/{{.BizPath}}{{.RelativePath}}"
+{{- end}}
+	"{{.RootPackage}}/internal/pkg/errno"
+	v1 "{{.RootPackage}}/{{.RequestPath}}{{.RelativePath}}"
+)
+
+// fake{{.StructName}}IBiz mocks biz.IBiz, methods other than {{.StructName}}() are not implemented.
+type fake{{.StructName}}IBiz struct {
+	biz.IBiz
+
+	{{.VariableName}} *fake{{.StructName}}Biz
+}
+
+func (b *fake{{.StructName}}IBiz) {{.StructName}}() {{if .RelativePath}}{{.VariableName}}biz{{else}}biz{{end}}.{{.StructName}}Biz {
+	return b.{{.VariableName}}
+}
+
+// fake{{.StructName}}Biz mocks {{.StructName}}Biz, all methods return err if set.
+type fake{{.StructName}}Biz struct {
+	{{if .RelativePath}}{{.VariableName}}biz{{else}}biz{{end}}.{{.StructName}}Biz
+
+	err error
+}
+
+func (b *fake{{.StructName}}Biz) List(ctx context.Context, req *v1.List{{.StructName}}Request) (*v1.List{{.StructName}}Response, error) {
+	return &v1.List{{.StructName}}Response{}, b.err
+}
+
+func (b *fake{{.StructName}}Biz) Create(ctx context.Context, req *v1.Create{{.StructName}}Request) (*v1.{{.StructName}}Info, error) {
+	return &v1.{{.StructName}}Info{}, b.err
+}
+
+func (b *fake{{.StructName}}Biz) Get(ctx context.Context, ID uint) (*v1.{{.StructName}}Info, error) {
+	return &v1.{{.StructName}}Info{}, b.err
+}
+
+func (b *fake{{.StructName}}Biz) Update(ctx context.Context, ID uint, req *v1.Update{{.StructName}}Request) (*v1.{{.StructName}}Info, error) {
+	return &v1.{{.StructName}}Info{}, b.err
+}
+
+func (b *fake{{.StructName}}Biz) Delete(ctx context.Context, ID uint) error {
+	return b.err
+}
+
+func newTest{{.StructName}}Router(b *fake{{.StructName}}Biz) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	h := &{{.StructName}}Handler{b: &fake{{.StructName}}IBiz{ {{- .VariableName}}: b}}
+
+	r := gin.New()
+	r.GET("/v1/{{.VariableNamePlural}}", h.List)
+	r.POST("/v1/{{.VariableNamePlural}}", h.Create)
+	r.GET("/v1/{{.VariableNamePlural}}/:id", h.Get)
+	r.PUT("/v1/{{.VariableNamePlural}}/:id", h.Update)
+	r.DELETE("/v1/{{.VariableNamePlural}}/:id", h.Delete)
+
+	return r
+}
+
+func Test{{.StructName}}Handler(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "List", method: http.MethodGet, path: "/v1/{{.VariableNamePlural}}"},
+		{name: "Create", method: http.MethodPost, path: "/v1/{{.VariableNamePlural}}", body: "{}"},
+		{name: "Get", method: http.MethodGet, path: "/v1/{{.VariableNamePlural}}/1"},
+		{name: "Update", method: http.MethodPut, path: "/v1/{{.VariableNamePlural}}/1", body: "{}"},
+		{name: "Delete", method: http.MethodDelete, path: "/v1/{{.VariableNamePlural}}/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTest{{.StructName}}Router(&fake{{.StructName}}Biz{})
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func Test{{.StructName}}Handler_Error(t *testing.T) {
+	r := newTest{{.StructName}}Router(&fake{{.StructName}}Biz{err: errno.ErrNotFound})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/{{.VariableNamePlural}}/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code == http.StatusOK {
+		t.Errorf("Expected error status, got %d: %s", w.Code, w.Body.String())
+	}
+}
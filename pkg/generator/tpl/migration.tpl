@@ -14,12 +14,12 @@ func ({{.StructName}}) TableName() string {
 	return "{{.TableName}}"
 }
 
-func ({{.StructName}}) Up(migrator gorm.Migrator) {
-	_ = migrator.AutoMigrate(&{{.StructName}}{})
+func ({{.StructName}}) Up(migrator gorm.Migrator) error {
+	return migrator.AutoMigrate(&{{.StructName}}{})
 }
 
-func ({{.StructName}}) Down(migrator gorm.Migrator) {
-	_ = migrator.DropTable(&{{.StructName}}{})
+func ({{.StructName}}) Down(migrator gorm.Migrator) error {
+	return migrator.DropTable(&{{.StructName}}{})
 }
 
 func init() {
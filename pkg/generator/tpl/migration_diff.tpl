@@ -0,0 +1,67 @@
+package {{.PackageName}}
+
+import (
+{{- range .SchemaDiff.StdImports}}
+	{{.}}
+{{- end}}
+{{- if .SchemaDiff.StdImports}}
+{{end}}
+	"gorm.io/gorm"
+{{- range .SchemaDiff.Imports}}
+	{{.}}
+{{- end}}
+
+	"github.com/bingo-project/bingoctl/pkg/migrate"
+{{- range .SchemaDiff.LocalImports}}
+	{{.}}
+{{- end}}
+)
+{{if gt (len .SchemaDiff.Tables) 1}}
+type {{.StructName}} struct{}
+{{end}}
+{{- range .SchemaDiff.Tables}}
+type {{.StructName}} struct {
+{{- range .Fields}}
+	{{.}}
+{{- end}}
+}
+
+func ({{.StructName}}) TableName() string {
+	return "{{.Table}}"
+}
+{{if .OldFields}}
+// {{.StructName}}Old has the column types before the migration, used to revert altered columns.
+type {{.StructName}}Old struct {
+{{- range .OldFields}}
+	{{.}}
+{{- end}}
+}
+
+func ({{.StructName}}Old) TableName() string {
+	return "{{.Table}}"
+}
+{{end}}
+{{- end}}
+func ({{.StructName}}) Up(migrator gorm.Migrator) error {
+{{- range .SchemaDiff.Up}}
+	if err := migrator.{{.}}; err != nil {
+		return err
+	}
+{{- end}}
+
+	return nil
+}
+
+func ({{.StructName}}) Down(migrator gorm.Migrator) error {
+{{- range .SchemaDiff.Down}}
+	if err := migrator.{{.}}; err != nil {
+		return err
+	}
+{{- end}}
+
+	return nil
+}
+
+func init() {
+	migrate.Add("{{.TimeStr}}_{{.VariableNameSnake}}", {{.StructName}}{}.Up, {{.StructName}}{}.Down)
+}
@@ -1,6 +1,9 @@
 package {{.PackageName}}
 
 import (
+{{- if .UsesTime}}
+	"time"
+{{end}}
 	"gorm.io/gorm"
 )
 
@@ -1,16 +1,37 @@
 package {{.PackageName}}
+{{- if .SeederRegister}}
+
+import (
+	"github.com/bingo-project/bingoctl/pkg/seed"
+)
+{{- end}}
 
 type {{.StructName}} struct {
 }
+{{- if .SeederRegister}}
+
+func init() {
+	seed.Register({{.StructName}}{})
+}
+{{- end}}
 
 // Signature The name and signature of the seeder.
 func ({{.StructName}}) Signature() string {
 	return "{{.StructName}}"
 }
 
+// Dependencies The signatures of the seeders to run before this one, e.g. "RoleSeeder".
+func ({{.StructName}}) Dependencies() []string {
+	return nil
+}
+
 // Run seed the application's database.
 func ({{.StructName}}) Run() error {
+{{- if .SeederRegister}}
+	// db := seed.DB()
+{{- else}}
 	//
+{{- end}}
 
 	return nil
 }
@@ -40,10 +40,9 @@ func New{{.StructName}}Store(store *datastore) *{{.VariableName}}Store {
 }
 
 func (s *{{.VariableName}}Store) ListWithRequest(ctx context.Context, req *v1.List{{.StructName}}Request) (int64, []*model.{{.StructName}}M, error) {
-	opts := where.NewWhere()
+	db := s.DB(ctx, where.NewWhere())
 	{{.UpdatableFields}}
 
-	db := s.DB(ctx, opts)
 	var ret []*model.{{.StructName}}M
 	count, err := gormutil.Paginate(db, &req.ListOptions, &ret)
 
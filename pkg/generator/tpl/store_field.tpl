@@ -1,3 +1,3 @@
 		if req.{{.Name}} != nil {
-    		db.Where("{{.NameSnake}} = ?", req.{{.Name}})
+    		db = db.Where("{{.NameSnake}} = ?", req.{{.Name}})
 		}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package {{.PackageName}}
+
+import (
+	"context"
+	"testing"
+{{- if .StoreTestFieldsUse "time"}}
+	"time"
+{{- end}}
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	model "{{.RootPackage}}/{{.ModelPath}}{{.RelativePath}}"
+	v1 "{{.RootPackage}}/{{.RequestPath}}{{.RelativePath}}"
+)
+
+// newTest{{.StructName}}Store returns a {{.VariableName}} store backed by a shared in-memory SQLite database,
+// so it works with the datastore singleton when several store tests run in the same package.
+// The rows of previous runs are deleted so unique columns don't conflict.
+func newTest{{.StructName}}Store(t *testing.T) *{{.VariableName}}Store {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open sqlite: %v", err)
+	}
+
+	if err := db.AutoMigrate(&model.{{.StructName}}M{}); err != nil {
+		t.Fatalf("Failed to migrate {{.TableName}}: %v", err)
+	}
+	if err := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&model.{{.StructName}}M{}).Error; err != nil {
+		t.Fatalf("Failed to clean {{.TableName}}: %v", err)
+	}
+
+	return New{{.StructName}}Store(NewStore(db))
+}
+
+func Test{{.StructName}}Store(t *testing.T) {
+	ctx := context.Background()
+	s := newTest{{.StructName}}Store(t)
+
+	{{.VariableName}} := &model.{{.StructName}}M{ {{- if .StoreTestFields}}
+{{- range .StoreTestFields}}
+		{{.Name}}: {{.Value}},
+{{- end}}
+	{{end}}}
+	if err := s.Create(ctx, {{.VariableName}}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	got, err := s.GetByID(ctx, {{.VariableName}}.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.ID != {{.VariableName}}.ID {
+		t.Errorf("Expected ID %d, got %d", {{.VariableName}}.ID, got.ID)
+	}
+
+	count, _, err := s.ListWithRequest(ctx, &v1.List{{.StructName}}Request{})
+	if err != nil {
+		t.Fatalf("ListWithRequest failed: %v", err)
+	}
+	if count < 1 {
+		t.Errorf("Expected at least 1 {{.VariableName}}, got %d", count)
+	}
+
+	if err := s.DeleteByID(ctx, {{.VariableName}}.ID); err != nil {
+		t.Fatalf("DeleteByID failed: %v", err)
+	}
+	if _, err := s.GetByID(ctx, {{.VariableName}}.ID); err == nil {
+		t.Error("Expected error after delete, got nil")
+	}
+}
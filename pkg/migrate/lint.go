@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mgutz/ansi"
+)
+
+// ErrLintFailed is returned by PrintLint when the migrations have errors.
+var ErrLintFailed = errors.New("migration lint failed")
+
+// timestampLayout is the layout of the timestamp prefix of migration names.
+const timestampLayout = "2006_01_02_150405"
+
+// LintIssue is a problem of a registered migration found by Lint.
+type LintIssue struct {
+	Migration string
+	Message   string
+
+	// Warning is set for issues which don't fail the lint.
+	Warning bool
+}
+
+// Lint checks the registered migrations without a database. Names must be unique and start with a
+// timestamp like 2006_01_02_150405_, migrations which can't be rolled back are reported as warnings.
+func Lint() []LintIssue {
+	var issues []LintIssue
+	for _, name := range duplicateMigrations() {
+		issues = append(issues, LintIssue{Migration: name, Message: "registered more than once"})
+	}
+
+	for _, migrationFile := range sortedMigrationFiles() {
+		name := migrationFile.FileName
+		if !validTimestamp(name) {
+			issues = append(issues, LintIssue{
+				Migration: name,
+				Message:   fmt.Sprintf("name doesn't start with a timestamp like %s_", timestampLayout),
+			})
+		}
+
+		if migrationFile.Up != nil && migrationFile.Down == nil {
+			issues = append(issues, LintIssue{Migration: name, Message: "has no down migration, it can't be rolled back", Warning: true})
+		}
+	}
+
+	return issues
+}
+
+// PrintLint writes the issues found by Lint to w. It returns ErrLintFailed if any issue isn't a warning.
+func PrintLint(w io.Writer) error {
+	issues := Lint()
+
+	failed := 0
+	for _, issue := range issues {
+		label := ansi.Color("Error:", "red")
+		if issue.Warning {
+			label = ansi.Color("Warning:", "yellow")
+		} else {
+			failed++
+		}
+
+		fmt.Fprintf(w, "%s %s %s\n", label, issue.Migration, issue.Message)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d errors", ErrLintFailed, failed)
+	}
+
+	fmt.Fprintf(w, "%s %d migrations checked.\n", ansi.Color("OK:", "green"), len(migrationFiles))
+
+	return nil
+}
+
+// validTimestamp returns true if name starts with a valid timestamp followed by an underscore.
+func validTimestamp(name string) bool {
+	if len(name) <= len(timestampLayout)+1 || name[len(timestampLayout)] != '_' {
+		return false
+	}
+
+	_, err := time.Parse(timestampLayout, name[:len(timestampLayout)])
+
+	return err == nil
+}
@@ -0,0 +1,231 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"time"
+
+	"github.com/bingo-project/component-base/cli/console"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DefaultLockTimeout is how long to wait for another process running migrations.
+const DefaultLockTimeout = 30 * time.Second
+
+// lockRetryInterval is the interval between attempts to take a lock that can't wait by itself.
+const lockRetryInterval = 500 * time.Millisecond
+
+// lockRefreshInterval is the interval at which the holder of a lock row refreshes its time, so a row
+// left behind by a crashed process is told apart from a lock held by a long migration.
+const lockRefreshInterval = 5 * time.Second
+
+// ErrLocked is returned when the migration lock isn't acquired within the lock timeout.
+var ErrLocked = errors.New("another process is running migrations")
+
+// migrationLock is the lock row used by dialects without advisory locks, e.g. SQLite.
+type migrationLock struct {
+	ID       int    `gorm:"primaryKey;autoIncrement:false"`
+	Owner    string `gorm:"type:varchar(255)"`
+	LockedAt time.Time
+}
+
+// TableName returns the migration table name suffixed with _lock.
+func (migrationLock) TableName() string {
+	return migrationTableName + "_lock"
+}
+
+// withLock runs fn holding the migration lock. Pretending doesn't change anything, so it doesn't lock.
+func (migrator *Migrator) withLock(fn func() error) error {
+	if migrator.Pretend {
+		return fn()
+	}
+
+	unlock, err := migrator.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn()
+}
+
+// lock takes the migration lock, waiting at most LockTimeout. It returns the function releasing the lock.
+// MySQL and PostgreSQL use advisory locks which are released when the connection is closed,
+// other dialects insert a row into the lock table.
+func (migrator *Migrator) lock() (func(), error) {
+	timeout := migrator.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+
+	switch migrator.DB.Dialector.Name() {
+	case "mysql":
+		return migrator.lockMySQL(timeout)
+	case "postgres":
+		return migrator.lockPostgres(timeout)
+	default:
+		return migrator.lockTable(timeout)
+	}
+}
+
+// lockName returns the name of the lock, unique per database and migration table.
+func (migrator *Migrator) lockName() string {
+	name := fmt.Sprintf("bingo_migrate:%s.%s", migrator.DB.Migrator().CurrentDatabase(), migrationTableName)
+
+	// MySQL lock names are limited to 64 characters.
+	if len(name) > 64 {
+		name = fmt.Sprintf("bingo_migrate:%x", lockKey(name))
+	}
+
+	return name
+}
+
+func (migrator *Migrator) lockMySQL(timeout time.Duration) (func(), error) {
+	name := migrator.lockName()
+	conn, err := migrator.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, int(math.Ceil(timeout.Seconds()))).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("%w: lock %s not acquired within %s", ErrLocked, name, timeout)
+	}
+
+	return func() {
+		var released sql.NullInt64
+		_ = conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", name).Scan(&released)
+		conn.Close()
+	}, nil
+}
+
+func (migrator *Migrator) lockPostgres(timeout time.Duration) (func(), error) {
+	name := migrator.lockName()
+	key := lockKey(name)
+	conn, err := migrator.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	deadline := time.Now().Add(timeout)
+	for {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if acquired {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			conn.Close()
+			return nil, fmt.Errorf("%w: lock %s not acquired within %s", ErrLocked, name, timeout)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	return func() {
+		var released bool
+		_ = conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", key).Scan(&released)
+		conn.Close()
+	}, nil
+}
+
+func (migrator *Migrator) lockTable(timeout time.Duration) (func(), error) {
+	if !migrator.Migrator.HasTable(&migrationLock{}) {
+		if err := migrator.Migrator.CreateTable(&migrationLock{}); err != nil {
+			return nil, err
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d", hostname, os.Getpid())
+
+	// Failing to insert the row is expected while another process holds the lock, don't log it.
+	db := migrator.DB.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+	// The holder refreshes the row, one not refreshed within the lock timeout was left by a crashed process.
+	staleAfter := max(timeout, 2*lockRefreshInterval)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := db.Create(&migrationLock{ID: 1, Owner: owner, LockedAt: time.Now()}).Error
+		if err == nil {
+			break
+		}
+
+		var holder migrationLock
+		if db.First(&holder).Error == nil && time.Since(holder.LockedAt) > staleAfter {
+			console.Warn(fmt.Sprintf("Removing the stale migration lock of %s, not refreshed since %s.",
+				holder.Owner, holder.LockedAt.Format(time.RFC3339)))
+			db.Where("id = ? AND owner = ?", holder.ID, holder.Owner).Delete(&migrationLock{})
+
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			if holder.Owner == "" {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("%w: lock held by %s since %s", ErrLocked, holder.Owner, holder.LockedAt.Format(time.RFC3339))
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				db.Model(&migrationLock{}).Where("id = ? AND owner = ?", 1, owner).Update("locked_at", time.Now())
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		migrator.DB.Where("id = ? AND owner = ?", 1, owner).Delete(&migrationLock{})
+	}, nil
+}
+
+// conn returns a dedicated connection, advisory locks belong to the connection which took them.
+func (migrator *Migrator) conn() (*sql.Conn, error) {
+	sqlDB, err := migrator.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlDB.Conn(context.Background())
+}
+
+// lockKey returns the 64-bit key of an advisory lock name.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+
+	return int64(h.Sum64())
+}
@@ -1,10 +1,19 @@
 package migrate
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	"gorm.io/gorm"
 )
 
-type migrationFunc func(gorm.Migrator)
+// ErrDuplicateMigration is returned when several migrations are registered with the same name.
+var ErrDuplicateMigration = errors.New("duplicate migration")
+
+// migrationFunc is the form every up and down function passed to Add is converted to.
+type migrationFunc func(db *gorm.DB) error
 
 var migrationFiles []MigrationFile
 
@@ -14,11 +23,20 @@ type MigrationFile struct {
 	FileName string
 }
 
-func Add(name string, up migrationFunc, down migrationFunc) {
+// Add registers a migration. up and down may be nil or a function of one of the forms:
+//
+//	func(gorm.Migrator)
+//	func(gorm.Migrator) error
+//	func(*gorm.DB) error
+//
+// Use *gorm.DB to change data along with the schema. Returning an error stops the migration
+// and the migration is not recorded as ran. Migrations run ordered by name, whatever the order
+// they're registered in.
+func Add(name string, up any, down any) {
 	migrationFiles = append(migrationFiles, MigrationFile{
 		FileName: name,
-		Up:       up,
-		Down:     down,
+		Up:       toMigrationFunc(name, up),
+		Down:     toMigrationFunc(name, down),
 	})
 }
 
@@ -31,3 +49,70 @@ func GetMigrationFile(name string) MigrationFile {
 
 	return MigrationFile{}
 }
+
+// sortedMigrationFiles returns the registered migrations ordered by name, the timestamp prefix of the
+// names orders them by creation. Migrations with the same name keep their registration order.
+func sortedMigrationFiles() []MigrationFile {
+	files := make([]MigrationFile, len(migrationFiles))
+	copy(files, migrationFiles)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].FileName < files[j].FileName
+	})
+
+	return files
+}
+
+// checkDuplicates returns ErrDuplicateMigration if several migrations are registered with the same name.
+func checkDuplicates() error {
+	if names := duplicateMigrations(); len(names) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateMigration, strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// duplicateMigrations returns the names registered more than once.
+func duplicateMigrations() []string {
+	var names []string
+	files := sortedMigrationFiles()
+	for i := 1; i < len(files); i++ {
+		if files[i].FileName == files[i-1].FileName && (len(names) == 0 || names[len(names)-1] != files[i].FileName) {
+			names = append(names, files[i].FileName)
+		}
+	}
+
+	return names
+}
+
+func toMigrationFunc(name string, fn any) migrationFunc {
+	switch fn := fn.(type) {
+	case nil:
+		return nil
+	case func(gorm.Migrator):
+		if fn == nil {
+			return nil
+		}
+
+		return func(db *gorm.DB) error {
+			fn(db.Migrator())
+
+			return nil
+		}
+	case func(gorm.Migrator) error:
+		if fn == nil {
+			return nil
+		}
+
+		return func(db *gorm.DB) error {
+			return fn(db.Migrator())
+		}
+	case func(*gorm.DB) error:
+		if fn == nil {
+			return nil
+		}
+
+		return fn
+	default:
+		panic(fmt.Sprintf("migrate: unsupported function %T for migration %s", fn, name))
+	}
+}
@@ -1,8 +1,12 @@
 package migrate
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/bingo-project/component-base/cli/console"
 	"github.com/mgutz/ansi"
@@ -12,6 +16,9 @@ import (
 // Default table name for migration records
 const DefaultTableName = "bingo_migration"
 
+// ErrOutOfOrder is returned by Up when pending migrations are older than the last ran migration.
+var ErrOutOfOrder = errors.New("out-of-order migrations")
+
 // migrationTableName stores the configured table name
 var migrationTableName = DefaultTableName
 
@@ -32,6 +39,18 @@ func SetTableName(name string) {
 type Migrator struct {
 	DB       *gorm.DB
 	Migrator gorm.Migrator
+
+	// Pretend prints the SQL of the migrations instead of running them, the migration table is left untouched.
+	Pretend bool
+
+	// LockTimeout is how long to wait for another process running migrations, DefaultLockTimeout if 0.
+	LockTimeout time.Duration
+
+	// AllowOutOfOrder runs pending migrations older than the last ran migration instead of refusing to.
+	AllowOutOfOrder bool
+
+	// SchemaDir is the directory of the schema dump loaded into an empty database, DefaultSchemaDir if empty.
+	SchemaDir string
 }
 
 type Migration struct {
@@ -79,62 +98,248 @@ func (migrator *Migrator) createMigrationsTable() {
 	}
 }
 
-func (migrator *Migrator) Up() {
+// Up runs all pending migrations.
+func (migrator *Migrator) Up() error {
+	return migrator.UpSteps(0)
+}
+
+// UpSteps runs at most steps pending migrations, all of them if steps is 0.
+// It stops at the first migration that fails.
+func (migrator *Migrator) UpSteps(steps int) error {
+	if err := checkDuplicates(); err != nil {
+		return err
+	}
+
+	return migrator.withLock(func() error {
+		return migrator.upSteps(steps)
+	})
+}
+
+func (migrator *Migrator) upSteps(steps int) error {
+	squashed, err := migrator.loadSchema()
+	if err != nil {
+		return err
+	}
+
 	// Get batch
 	batch := migrator.getBatch()
 
 	var migrations []Migration
-	migrator.DB.Find(&migrations)
+	if err := migrator.DB.Find(&migrations).Error; err != nil {
+		return err
+	}
 
-	ran := false
-	for _, migrationFile := range migrationFiles {
+	// Pretending doesn't record the squashed migrations of the schema dump.
+	if migrator.Pretend {
+		migrations = append(migrations, squashed...)
+	}
+
+	var pending []MigrationFile
+	for _, migrationFile := range sortedMigrationFiles() {
 		if isNotMigrated(migrations, migrationFile) {
-			migrator.runUpMigration(migrationFile, batch)
-			ran = true
+			pending = append(pending, migrationFile)
 		}
 	}
 
-	if !ran {
+	if err := migrator.checkOrder(pending, migrations); err != nil {
+		return err
+	}
+
+	ran := 0
+	for _, migrationFile := range pending {
+		if steps > 0 && ran >= steps {
+			break
+		}
+
+		if err := migrator.runUpMigration(migrationFile, batch); err != nil {
+			return err
+		}
+		ran++
+	}
+
+	if ran == 0 {
 		console.Info("Nothing to migrate.")
 	}
+
+	return nil
+}
+
+// checkOrder warns about pending migrations older than the last ran migration, e.g. merged from
+// another branch. They're refused unless AllowOutOfOrder is set.
+func (migrator *Migrator) checkOrder(pending []MigrationFile, migrations []Migration) error {
+	var last string
+	for _, migration := range migrations {
+		if migration.Migration > last {
+			last = migration.Migration
+		}
+	}
+
+	var outOfOrder []string
+	for _, migrationFile := range pending {
+		if migrationFile.FileName < last {
+			outOfOrder = append(outOfOrder, migrationFile.FileName)
+		}
+	}
+
+	if len(outOfOrder) == 0 {
+		return nil
+	}
+
+	for _, name := range outOfOrder {
+		console.Warn(fmt.Sprintf("Pending migration %s is older than the last ran migration %s.", name, last))
+	}
+
+	if migrator.AllowOutOfOrder {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d pending migrations are older than %s, use --allow-out-of-order to run them",
+		ErrOutOfOrder, len(outOfOrder), last)
+}
+
+// Rollback rolls back the last batch of migrations.
+func (migrator *Migrator) Rollback() error {
+	return migrator.withLock(migrator.rollbackLastBatch)
 }
 
-func (migrator *Migrator) Rollback() {
+func (migrator *Migrator) rollbackLastBatch() error {
 	var maxBatch *int
 	migrator.DB.Model(&Migration{}).Select("MAX(batch)").Scan(&maxBatch)
 
 	if maxBatch == nil {
 		console.Info("Nothing to rollback.")
-		return
+		return nil
+	}
+
+	return migrator.rollbackBatch(*maxBatch)
+}
+
+// RollbackBatch rolls back the migrations of batch.
+func (migrator *Migrator) RollbackBatch(batch int) error {
+	return migrator.withLock(func() error {
+		return migrator.rollbackBatch(batch)
+	})
+}
+
+func (migrator *Migrator) rollbackBatch(batch int) error {
+	var migrations []Migration
+	migrator.DB.Where("batch = ?", batch).Order("id DESC").Find(&migrations)
+
+	return migrator.rollback(migrations)
+}
+
+// RollbackSteps rolls back the last steps migrations, across batches.
+func (migrator *Migrator) RollbackSteps(steps int) error {
+	return migrator.withLock(func() error {
+		var migrations []Migration
+		migrator.DB.Order("batch DESC, id DESC").Limit(steps).Find(&migrations)
+
+		return migrator.rollback(migrations)
+	})
+}
+
+// Goto runs or rolls back migrations so that name is the last migration ran: migrations registered
+// after name are rolled back, latest first, then pending migrations up to and including name are run.
+func (migrator *Migrator) Goto(name string) error {
+	if err := checkDuplicates(); err != nil {
+		return err
+	}
+
+	target := migrationIndex(name)
+	if target < 0 {
+		return fmt.Errorf("migration not found: %s", name)
 	}
 
+	return migrator.withLock(func() error {
+		return migrator.goTo(name, target)
+	})
+}
+
+func (migrator *Migrator) goTo(name string, target int) error {
 	var migrations []Migration
-	migrator.DB.Where("batch = ?", *maxBatch).Order("id DESC").Find(&migrations)
+	if err := migrator.DB.Find(&migrations).Error; err != nil {
+		return err
+	}
 
-	if !migrator.rollbackMigrations(migrations) {
+	// Roll back
+	var rollback []Migration
+	for _, migration := range migrations {
+		if migrationIndex(migration.Migration) > target {
+			rollback = append(rollback, migration)
+		}
+	}
+	sort.Slice(rollback, func(i, j int) bool {
+		return migrationIndex(rollback[i].Migration) > migrationIndex(rollback[j].Migration)
+	})
+	rolledBack, err := migrator.rollbackMigrations(rollback)
+	if err != nil {
+		return err
+	}
+
+	// Run
+	batch := migrator.getBatch()
+	ran := false
+	for _, migrationFile := range sortedMigrationFiles()[:target+1] {
+		if isNotMigrated(migrations, migrationFile) {
+			if err := migrator.runUpMigration(migrationFile, batch); err != nil {
+				return err
+			}
+			ran = true
+		}
+	}
+
+	if !rolledBack && !ran {
+		console.Info("Already at " + name + ".")
+	}
+
+	return nil
+}
+
+func (migrator *Migrator) rollback(migrations []Migration) error {
+	ran, err := migrator.rollbackMigrations(migrations)
+	if err != nil {
+		return err
+	}
+
+	if !ran {
 		console.Info("Nothing to rollback.")
 	}
+
+	return nil
 }
 
-func (migrator *Migrator) rollbackMigrations(migrations []Migration) bool {
+// rollbackMigrations rolls back migrations in order, it stops at the first migration that fails.
+func (migrator *Migrator) rollbackMigrations(migrations []Migration) (bool, error) {
 	ran := false
 
 	for _, _migration := range migrations {
+		migrationFile := GetMigrationFile(_migration.Migration)
+		if migrator.Pretend {
+			if err := migrator.pretend(_migration.Migration, "down", migrationFile.Down); err != nil {
+				return ran, err
+			}
+			ran = true
+
+			continue
+		}
+
 		fmt.Printf("%s %s\n", ansi.Color("Rolling back:", "yellow"), _migration.Migration)
 
-		migrationFile := GetMigrationFile(_migration.Migration)
-		if migrationFile.Down != nil {
-			migrationFile.Down(migrator.DB.Migrator())
+		err := migrator.run(migrationFile.Down, func(db *gorm.DB) error {
+			return db.Delete(&_migration).Error
+		})
+		if err != nil {
+			fmt.Printf("%s  %s\n", ansi.Color("Failed:", "red"), _migration.Migration)
+
+			return ran, fmt.Errorf("rollback %s: %w", _migration.Migration, err)
 		}
 
 		ran = true
 
-		migrator.DB.Delete(&_migration)
-
-		fmt.Printf("%s  %s\n", ansi.Color("Rolled back:", "green"), migrationFile.FileName)
+		fmt.Printf("%s  %s\n", ansi.Color("Rolled back:", "green"), _migration.Migration)
 	}
 
-	return ran
+	return ran, nil
 }
 
 func (migrator *Migrator) getBatch() int {
@@ -148,46 +353,126 @@ func (migrator *Migrator) getBatch() int {
 	return *maxBatch + 1
 }
 
-func (migrator *Migrator) runUpMigration(migrationFile MigrationFile, batch int) {
+func (migrator *Migrator) runUpMigration(migrationFile MigrationFile, batch int) error {
+	if migrator.Pretend {
+		return migrator.pretend(migrationFile.FileName, "up", migrationFile.Up)
+	}
+
 	if migrationFile.Up != nil {
 		fmt.Printf("%s %s\n", ansi.Color("Migrating:", "yellow"), migrationFile.FileName)
+	}
 
-		migrationFile.Up(migrator.DB.Migrator())
+	err := migrator.run(migrationFile.Up, func(db *gorm.DB) error {
+		return db.Create(&Migration{Migration: migrationFile.FileName, Batch: batch}).Error
+	})
+	if err != nil {
+		fmt.Printf("%s  %s\n", ansi.Color("Failed:", "red"), migrationFile.FileName)
+
+		return fmt.Errorf("migrate %s: %w", migrationFile.FileName, err)
+	}
 
+	if migrationFile.Up != nil {
 		fmt.Printf("%s  %s\n", ansi.Color("Migrated:", "green"), migrationFile.FileName)
 	}
 
-	err := migrator.DB.Create(&Migration{Migration: migrationFile.FileName, Batch: batch}).Error
-	console.ExitIf(err)
+	return nil
 }
 
-func (migrator *Migrator) Reset() {
+// run runs fn and then record, which updates the migration table. Where the dialect supports
+// transactional DDL both run in a transaction, so a failed migration leaves nothing behind.
+// Otherwise record only runs if fn succeeds.
+func (migrator *Migrator) run(fn migrationFunc, record func(db *gorm.DB) error) error {
+	run := func(db *gorm.DB) error {
+		if fn != nil {
+			if err := fn(db); err != nil {
+				return err
+			}
+		}
+
+		return record(db)
+	}
+
+	if !migrator.transactional() {
+		return run(migrator.DB)
+	}
+
+	return migrator.DB.Transaction(run)
+}
+
+// transactional returns true if schema changes can be rolled back, MySQL commits DDL implicitly.
+func (migrator *Migrator) transactional() bool {
+	switch migrator.DB.Dialector.Name() {
+	case "postgres", "sqlite":
+		return true
+	default:
+		return false
+	}
+}
+
+// Reset rolls back all migrations.
+func (migrator *Migrator) Reset() error {
+	return migrator.withLock(migrator.reset)
+}
+
+func (migrator *Migrator) reset() error {
 	var migrations []Migration
 
 	migrator.DB.Order("id DESC").Find(&migrations)
 
-	if !migrator.rollbackMigrations(migrations) {
-		console.Info("Nothing to rollback.")
+	return migrator.rollback(migrations)
+}
+
+// Refresh rolls back all migrations and runs them again.
+func (migrator *Migrator) Refresh() error {
+	if err := checkDuplicates(); err != nil {
+		return err
 	}
+
+	return migrator.withLock(func() error {
+		if err := migrator.reset(); err != nil {
+			return err
+		}
+
+		return migrator.upSteps(0)
+	})
 }
 
-func (migrator *Migrator) Refresh() {
-	migrator.Reset()
+// Fresh drops all tables and runs all migrations.
+func (migrator *Migrator) Fresh() error {
+	if err := checkDuplicates(); err != nil {
+		return err
+	}
 
-	migrator.Up()
+	if migrator.Pretend {
+		return migrator.pretendFresh()
+	}
+
+	return migrator.withLock(migrator.fresh)
 }
 
-func (migrator *Migrator) Fresh() {
+func (migrator *Migrator) fresh() error {
 	// Delete all tables
-	err := migrator.DeleteAllTables()
-	console.ExitIf(err)
+	if err := migrator.DeleteAllTables(); err != nil {
+		return err
+	}
 	console.Info("Dropped all tables successfully.")
 
 	// Migrate
 	migrator.createMigrationsTable()
 	console.Info("Migration table created successfully.")
 
-	migrator.Up()
+	return migrator.upSteps(0)
+}
+
+// migrationIndex returns the position of name in the migrations ordered by name, -1 if it's not registered.
+func migrationIndex(name string) int {
+	for i, migrationFile := range sortedMigrationFiles() {
+		if migrationFile.FileName == name {
+			return i
+		}
+	}
+
+	return -1
 }
 
 func isNotMigrated(migrations []Migration, migrationFile MigrationFile) bool {
@@ -200,17 +485,99 @@ func isNotMigrated(migrations []Migration, migrationFile MigrationFile) bool {
 	return true
 }
 
+// pretendFresh prints the statements dropping all tables followed by the schema dump and every migration
+// newer than it. The migrations are pretended against the current schema since nothing is dropped.
+func (migrator *Migrator) pretendFresh() error {
+	if err := migrator.pretend("drop all tables", "fresh", deleteAllTables); err != nil {
+		return err
+	}
+
+	dump, err := migrator.readSchema()
+	if err != nil {
+		return err
+	}
+	if dump != nil {
+		if err := migrator.load(dump); err != nil {
+			return err
+		}
+	}
+
+	for _, migrationFile := range sortedMigrationFiles() {
+		if dump != nil && !isNotMigrated(dump.Squashed, migrationFile) {
+			continue
+		}
+
+		if err := migrator.pretend(migrationFile.FileName, "up", migrationFile.Up); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (migrator *Migrator) DeleteAllTables() error {
-	tables, err := migrator.DB.Migrator().GetTables()
+	return deleteAllTables(migrator.DB)
+}
+
+func deleteAllTables(db *gorm.DB) error {
+	if err := dropViews(db); err != nil {
+		return err
+	}
+
+	tables, err := db.Migrator().GetTables()
 	if err != nil {
 		return err
 	}
 
+	if len(tables) == 0 {
+		return nil
+	}
+
+	// Drop all tables in one statement so foreign keys between them don't matter.
+	if db.Dialector.Name() == "postgres" {
+		quoted := make([]string, 0, len(tables))
+		for _, table := range tables {
+			quoted = append(quoted, db.Statement.Quote(table))
+		}
+
+		return db.Exec("DROP TABLE IF EXISTS " + strings.Join(quoted, ", ") + " CASCADE").Error
+	}
+
 	for _, table := range tables {
-		err := migrator.DB.Migrator().DropTable(table)
-		if err != nil {
+		// SQLite internal tables (e.g. sqlite_sequence) can't be dropped, the lock table is in use.
+		if strings.HasPrefix(table, "sqlite_") || table == (migrationLock{}).TableName() {
 			continue
 		}
+
+		if err := db.Migrator().DropTable(table); err != nil {
+			return fmt.Errorf("drop table %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// dropViews drops the views of the database, e.g. created by SQL migrations or loaded from the schema dump.
+func dropViews(db *gorm.DB) error {
+	var query string
+	switch db.Dialector.Name() {
+	case "sqlite":
+		query = "SELECT name FROM sqlite_master WHERE type = 'view'"
+	case "postgres":
+		query = "SELECT table_name FROM information_schema.views WHERE table_schema = CURRENT_SCHEMA()"
+	default:
+		query = "SELECT table_name FROM information_schema.views WHERE table_schema = DATABASE()"
+	}
+
+	var views []string
+	if err := db.Raw(query).Scan(&views).Error; err != nil {
+		return err
+	}
+
+	for _, view := range views {
+		if err := db.Exec("DROP VIEW IF EXISTS " + db.Statement.Quote(view)).Error; err != nil {
+			return err
+		}
 	}
 
 	return nil
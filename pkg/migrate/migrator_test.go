@@ -4,7 +4,13 @@
 package migrate
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -78,3 +84,433 @@ func TestRollback_RollsBackMaxBatch(t *testing.T) {
 		t.Errorf("expected remaining migration to be 'migration_2', got '%s'", remaining[0].Migration)
 	}
 }
+
+func TestFresh_DropsAllTablesOnSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)")
+	db.Exec("INSERT INTO users (name) VALUES ('bingo')")
+	db.Exec("INSERT INTO bingo_migration (migration, batch) VALUES ('migration_1', 1)")
+
+	migrator.Fresh()
+
+	if db.Migrator().HasTable("users") {
+		t.Error("expected users table to be dropped")
+	}
+
+	if !db.Migrator().HasTable(&Migration{}) {
+		t.Fatal("expected migration table to be recreated")
+	}
+
+	var count int64
+	db.Model(&Migration{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected empty migration table, got %d records", count)
+	}
+}
+
+func TestFresh_ReturnsDropTableError(t *testing.T) {
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY)")
+	db.Callback().Raw().Before("gorm:raw").Register("fail_drop", func(tx *gorm.DB) {
+		if strings.HasPrefix(tx.Statement.SQL.String(), "DROP TABLE") {
+			tx.AddError(errors.New("drop failed"))
+		}
+	})
+
+	if err := migrator.Fresh(); err == nil || !strings.Contains(err.Error(), "drop failed") {
+		t.Errorf("Fresh() error = %v, want the drop table error", err)
+	}
+}
+
+func TestStatus_MarksRanPendingAndOrphaned(t *testing.T) {
+	setupMigrationFiles(t, "migration_1", "migration_2")
+
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	db.Exec("INSERT INTO bingo_migration (migration, batch) VALUES ('migration_1', 2)")
+	db.Exec("INSERT INTO bingo_migration (migration, batch) VALUES ('deleted_migration', 1)")
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+
+	want := []MigrationStatus{
+		{Migration: "migration_1", Ran: true, Batch: 2},
+		{Migration: "migration_2"},
+		{Migration: "deleted_migration", Ran: true, Batch: 1, Orphaned: true},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("Status() = %+v, want %+v", statuses, want)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("Status()[%d] = %+v, want %+v", i, statuses[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := migrator.PrintStatus(&buf, OutputJSON); err != nil {
+		t.Fatalf("PrintStatus() failed: %v", err)
+	}
+	var decoded []MigrationStatus
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("PrintStatus() output is not valid json: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != len(want) || decoded[2] != want[2] {
+		t.Errorf("PrintStatus() json = %+v, want %+v", decoded, want)
+	}
+}
+
+func setupMigrationFiles(t *testing.T, names ...string) {
+	original := migrationFiles
+	t.Cleanup(func() { migrationFiles = original })
+
+	migrationFiles = nil
+	for _, name := range names {
+		Add(name, nil, nil)
+	}
+}
+
+func ranMigrations(db *gorm.DB) []string {
+	var names []string
+	db.Model(&Migration{}).Order("id").Pluck("migration", &names)
+
+	return names
+}
+
+func TestUpSteps_RunsAtMostSteps(t *testing.T) {
+	setupMigrationFiles(t, "migration_1", "migration_2", "migration_3")
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	migrator.UpSteps(2)
+
+	if got := ranMigrations(db); len(got) != 2 || got[1] != "migration_2" {
+		t.Errorf("ran migrations = %v, want [migration_1 migration_2]", got)
+	}
+}
+
+func TestRollbackSteps_AcrossBatches(t *testing.T) {
+	setupMigrationFiles(t, "migration_1", "migration_2", "migration_3")
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	db.Exec("INSERT INTO bingo_migration (migration, batch) VALUES ('migration_1', 1), ('migration_2', 1), ('migration_3', 2)")
+
+	migrator.RollbackSteps(2)
+
+	if got := ranMigrations(db); len(got) != 1 || got[0] != "migration_1" {
+		t.Errorf("ran migrations = %v, want [migration_1]", got)
+	}
+}
+
+func TestRollbackBatch_RollsBackGivenBatch(t *testing.T) {
+	setupMigrationFiles(t, "migration_1", "migration_2", "migration_3")
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	db.Exec("INSERT INTO bingo_migration (migration, batch) VALUES ('migration_1', 1), ('migration_2', 2), ('migration_3', 3)")
+
+	migrator.RollbackBatch(2)
+
+	if got := ranMigrations(db); len(got) != 2 || got[0] != "migration_1" || got[1] != "migration_3" {
+		t.Errorf("ran migrations = %v, want [migration_1 migration_3]", got)
+	}
+}
+
+func TestGoto_MovesForwardAndBackward(t *testing.T) {
+	setupMigrationFiles(t, "migration_1", "migration_2", "migration_3", "migration_4")
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	if err := migrator.Goto("migration_3"); err != nil {
+		t.Fatalf("Goto() failed: %v", err)
+	}
+	if got := ranMigrations(db); len(got) != 3 || got[2] != "migration_3" {
+		t.Errorf("ran migrations = %v, want migrations up to migration_3", got)
+	}
+
+	if err := migrator.Goto("migration_1"); err != nil {
+		t.Fatalf("Goto() failed: %v", err)
+	}
+	if got := ranMigrations(db); len(got) != 1 || got[0] != "migration_1" {
+		t.Errorf("ran migrations = %v, want [migration_1]", got)
+	}
+
+	if err := migrator.Goto("unknown"); err == nil {
+		t.Error("Goto() expected error for unknown migration")
+	}
+}
+
+type pretendPost struct {
+	ID    uint
+	Title string
+}
+
+func TestPretend_RecordsStatementsWithoutRunning(t *testing.T) {
+	setupMigrationFiles(t)
+	Add("create_posts_table",
+		func(m gorm.Migrator) { _ = m.CreateTable(&pretendPost{}) },
+		func(m gorm.Migrator) { _ = m.DropTable(&pretendPost{}) },
+	)
+
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+	migrator.Pretend = true
+
+	statements, err := migrator.pretendStatements(func(db *gorm.DB) error {
+		return db.Migrator().CreateTable(&pretendPost{})
+	})
+	if err != nil {
+		t.Fatalf("pretendStatements() failed: %v", err)
+	}
+	if len(statements) != 1 || !strings.Contains(statements[0], "CREATE TABLE `pretend_posts`") {
+		t.Errorf("statements = %v, want CREATE TABLE pretend_posts", statements)
+	}
+
+	migrator.Up()
+
+	if db.Migrator().HasTable(&pretendPost{}) {
+		t.Error("expected pretend up not to create the table")
+	}
+	if got := ranMigrations(db); len(got) != 0 {
+		t.Errorf("ran migrations = %v, want none", got)
+	}
+
+	// The connection of the migrator is still usable
+	migrator.Pretend = false
+	migrator.Up()
+	if !db.Migrator().HasTable(&pretendPost{}) {
+		t.Error("expected up to create the table")
+	}
+
+	migrator.Pretend = true
+	migrator.Fresh()
+	if !db.Migrator().HasTable(&pretendPost{}) {
+		t.Error("expected pretend fresh not to drop the table")
+	}
+	if got := ranMigrations(db); len(got) != 1 {
+		t.Errorf("ran migrations = %v, want [create_posts_table]", got)
+	}
+}
+
+func TestAdd_AcceptsMigrationFuncForms(t *testing.T) {
+	setupMigrationFiles(t)
+
+	var calls []string
+	Add("migrator", func(m gorm.Migrator) { calls = append(calls, "migrator") }, nil)
+	Add("migrator_error", func(m gorm.Migrator) error { calls = append(calls, "migrator_error"); return nil }, nil)
+	Add("db_error", func(db *gorm.DB) error { calls = append(calls, "db_error"); return nil }, nil)
+
+	db := setupTestDB(t)
+	if err := NewMigrator(db).Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+
+	if len(calls) != 3 || calls[0] != "db_error" || calls[2] != "migrator_error" {
+		t.Errorf("calls = %v, want all three migrations run ordered by name", calls)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Add to panic for an unsupported function")
+		}
+	}()
+	Add("unsupported", func() {}, nil)
+}
+
+func TestUp_StopsAtFailedMigration(t *testing.T) {
+	setupMigrationFiles(t)
+	Add("migration_1", nil, nil)
+	Add("migration_2", func(db *gorm.DB) error {
+		if err := db.Migrator().CreateTable(&pretendPost{}); err != nil {
+			return err
+		}
+
+		return errors.New("boom")
+	}, nil)
+	Add("migration_3", nil, nil)
+
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	err := migrator.Up()
+	if err == nil || !strings.Contains(err.Error(), "migration_2") {
+		t.Fatalf("Up() error = %v, want error of migration_2", err)
+	}
+
+	if got := ranMigrations(db); len(got) != 1 || got[0] != "migration_1" {
+		t.Errorf("ran migrations = %v, want [migration_1]", got)
+	}
+
+	// SQLite runs each migration in a transaction
+	if db.Migrator().HasTable(&pretendPost{}) {
+		t.Error("expected the table created by the failed migration to be rolled back")
+	}
+}
+
+func TestRollback_KeepsRecordOfFailedMigration(t *testing.T) {
+	setupMigrationFiles(t)
+	Add("migration_1", nil, func(m gorm.Migrator) error { return errors.New("boom") })
+
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+
+	if err := migrator.Rollback(); err == nil {
+		t.Fatal("Rollback() expected error")
+	}
+
+	if got := ranMigrations(db); len(got) != 1 {
+		t.Errorf("ran migrations = %v, want [migration_1]", got)
+	}
+}
+
+func TestLock_BlocksConcurrentMigrations(t *testing.T) {
+	setupMigrationFiles(t, "migration_1")
+	db := setupTestDB(t)
+
+	unlock, err := NewMigrator(db).lock()
+	if err != nil {
+		t.Fatalf("lock() failed: %v", err)
+	}
+
+	migrator := NewMigrator(db)
+	migrator.LockTimeout = 100 * time.Millisecond
+	if err := migrator.Up(); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Up() error = %v, want ErrLocked", err)
+	}
+	if got := ranMigrations(db); len(got) != 0 {
+		t.Errorf("ran migrations = %v, want none while locked", got)
+	}
+
+	unlock()
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() failed after unlock: %v", err)
+	}
+	if got := ranMigrations(db); len(got) != 1 {
+		t.Errorf("ran migrations = %v, want [migration_1]", got)
+	}
+
+	// The lock table survives fresh and the lock is released
+	if err := migrator.Fresh(); err != nil {
+		t.Fatalf("Fresh() failed: %v", err)
+	}
+	var locks int64
+	db.Model(&migrationLock{}).Count(&locks)
+	if locks != 0 {
+		t.Errorf("expected the lock released, got %d lock rows", locks)
+	}
+}
+
+func TestLock_RemovesStaleLockRow(t *testing.T) {
+	setupMigrationFiles(t, "migration_1")
+	db := setupTestDB(t)
+
+	// A process crashed while holding the lock, its row hasn't been refreshed since
+	if err := db.AutoMigrate(&migrationLock{}); err != nil {
+		t.Fatalf("failed to create the lock table: %v", err)
+	}
+	db.Create(&migrationLock{ID: 1, Owner: "gone:42", LockedAt: time.Now().Add(-time.Hour)})
+
+	migrator := NewMigrator(db)
+	migrator.LockTimeout = 100 * time.Millisecond
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() failed with a stale lock: %v", err)
+	}
+	if got := ranMigrations(db); len(got) != 1 {
+		t.Errorf("ran migrations = %v, want [migration_1]", got)
+	}
+
+	// A recently refreshed row is still held
+	db.Create(&migrationLock{ID: 1, Owner: "alive:42", LockedAt: time.Now()})
+	if err := migrator.Rollback(); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Rollback() error = %v, want ErrLocked", err)
+	}
+}
+
+func TestUp_RunsMigrationsOrderedByName(t *testing.T) {
+	setupMigrationFiles(t, "2024_01_03_000000_third", "2024_01_01_000000_first", "2024_01_02_000000_second")
+	db := setupTestDB(t)
+
+	if err := NewMigrator(db).Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+
+	want := "2024_01_01_000000_first,2024_01_02_000000_second,2024_01_03_000000_third"
+	if got := strings.Join(ranMigrations(db), ","); got != want {
+		t.Errorf("ran migrations = %s, want %s", got, want)
+	}
+}
+
+func TestUp_RefusesDuplicateMigrations(t *testing.T) {
+	setupMigrationFiles(t, "2024_01_01_000000_first", "2024_01_02_000000_second", "2024_01_01_000000_first")
+	db := setupTestDB(t)
+
+	err := NewMigrator(db).Up()
+	if !errors.Is(err, ErrDuplicateMigration) || !strings.Contains(err.Error(), "2024_01_01_000000_first") {
+		t.Fatalf("Up() error = %v, want %v", err, ErrDuplicateMigration)
+	}
+	if got := ranMigrations(db); len(got) != 0 {
+		t.Errorf("ran migrations = %v, want none", got)
+	}
+}
+
+func TestUp_OutOfOrderMigrations(t *testing.T) {
+	setupMigrationFiles(t, "2024_01_01_000000_first", "2024_01_02_000000_branch", "2024_01_03_000000_third")
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+
+	db.Exec("INSERT INTO bingo_migration (migration, batch) VALUES ('2024_01_01_000000_first', 1), ('2024_01_03_000000_third', 1)")
+
+	if err := migrator.Up(); !errors.Is(err, ErrOutOfOrder) {
+		t.Fatalf("Up() error = %v, want %v", err, ErrOutOfOrder)
+	}
+	if got := ranMigrations(db); len(got) != 2 {
+		t.Errorf("ran migrations = %v, want the out-of-order migration not run", got)
+	}
+
+	migrator.AllowOutOfOrder = true
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+	if got := ranMigrations(db); len(got) != 3 || got[2] != "2024_01_02_000000_branch" {
+		t.Errorf("ran migrations = %v, want the out-of-order migration run", got)
+	}
+}
+
+func TestLint_ReportsIssues(t *testing.T) {
+	setupMigrationFiles(t, "2024_01_01_000000_first", "2024_13_01_000000_bad_month", "create_posts", "2024_01_01_000000_first")
+	Add("2024_01_02_000000_with_down", func(db *gorm.DB) error { return nil }, func(db *gorm.DB) error { return nil })
+	Add("2024_01_03_000000_no_down", func(db *gorm.DB) error { return nil }, nil)
+
+	var got []string
+	for _, issue := range Lint() {
+		got = append(got, fmt.Sprintf("%s:%t", issue.Migration, issue.Warning))
+	}
+
+	want := "2024_01_01_000000_first:false,2024_01_03_000000_no_down:true,2024_13_01_000000_bad_month:false,create_posts:false"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Lint() = %v, want %s", got, want)
+	}
+
+	var out bytes.Buffer
+	if err := PrintLint(&out); !errors.Is(err, ErrLintFailed) {
+		t.Errorf("PrintLint() error = %v, want %v", err, ErrLintFailed)
+	}
+
+	setupMigrationFiles(t, "2024_01_01_000000_first")
+	out.Reset()
+	if err := PrintLint(&out); err != nil || !strings.Contains(out.String(), "1 migrations checked") {
+		t.Errorf("PrintLint() = %v, output %q", err, out.String())
+	}
+}
@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/mgutz/ansi"
+	"gorm.io/gorm"
+)
+
+// pretendPool records the statements that change the database instead of executing them.
+// Queries are passed through, so the migrator can still inspect the current schema.
+type pretendPool struct {
+	gorm.ConnPool
+
+	dialector  gorm.Dialector
+	statements []string
+}
+
+func (p *pretendPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	p.statements = append(p.statements, p.dialector.Explain(query, args...))
+
+	return driver.RowsAffected(0), nil
+}
+
+// pretendStatements runs fn against a session recording the statements it would execute.
+func (migrator *Migrator) pretendStatements(fn func(db *gorm.DB) error) ([]string, error) {
+	pool := &pretendPool{ConnPool: migrator.DB.Statement.ConnPool, dialector: migrator.DB.Dialector}
+
+	// A session with a context gets its own statement, so the connection pool of migrator.DB is kept.
+	tx := migrator.DB.Session(&gorm.Session{NewDB: true, Context: context.Background()})
+	tx.Statement.ConnPool = pool
+	tx.Config.ConnPool = pool
+
+	err := fn(tx)
+
+	return pool.statements, err
+}
+
+// pretend prints the statements fn of the migration would execute.
+func (migrator *Migrator) pretend(name, direction string, fn migrationFunc) error {
+	fmt.Printf("%s %s (%s)\n", ansi.Color("Pretending:", "yellow"), name, direction)
+
+	if fn == nil {
+		fmt.Println("  -- nothing to run")
+		return nil
+	}
+
+	statements, err := migrator.pretendStatements(fn)
+	printStatements(statements)
+	if err != nil {
+		return fmt.Errorf("pretend %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func printStatements(statements []string) {
+	if len(statements) == 0 {
+		fmt.Println("  -- no statements")
+		return
+	}
+
+	for _, statement := range statements {
+		fmt.Printf("  %s;\n", statement)
+	}
+}
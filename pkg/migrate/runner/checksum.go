@@ -1,5 +1,5 @@
 // ABOUTME: Calculates checksum of migration files for cache invalidation
-// ABOUTME: Uses SHA256 hash of all .go files in migration directory
+// ABOUTME: Uses SHA256 hash of all .go and .sql files in migration directory
 package runner
 
 import (
@@ -11,8 +11,8 @@ import (
 	"sort"
 )
 
-// CalculateChecksum calculates a SHA256 checksum of all .go files in the directory.
-// Returns empty string if directory doesn't exist or has no .go files.
+// CalculateChecksum calculates a SHA256 checksum of all .go and .sql files in the directory.
+// Returns empty string if directory doesn't exist or has no such files.
 func CalculateChecksum(dir string) (string, error) {
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		return "", nil
@@ -23,7 +23,7 @@ func CalculateChecksum(dir string) (string, error) {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() && filepath.Ext(path) == ".go" {
+		if !info.IsDir() && (filepath.Ext(path) == ".go" || filepath.Ext(path) == ".sql") {
 			files = append(files, path)
 		}
 		return nil
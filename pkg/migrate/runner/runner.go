@@ -4,7 +4,9 @@ package runner
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"os/exec"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/bingo-project/bingoctl/pkg/config"
 	"github.com/bingo-project/bingoctl/pkg/db"
+	"github.com/bingo-project/bingoctl/pkg/migrate"
 )
 
 //go:embed tpl/*.tpl
@@ -30,8 +33,9 @@ type Runner struct {
 	migrationPath string
 
 	// Database config
-	dbOptions    *db.MySQLOptions
+	dbOptions    *db.Options
 	migrateTable string
+	schemaDir    string // Schema dump directory of a named connection, the default if empty
 
 	// Directories
 	cacheDir string // ~/.bingo/migrator/<id>/ for binary
@@ -59,21 +63,27 @@ func NewRunner(verbose, rebuild bool) (*Runner, error) {
 	projectName := filepath.Base(userModule)
 
 	// Get migration directory from config
-	migrationDir := config.Cfg.Directory.Migration
+	migrationDir := config.Cfg.GetMigrationDirectory()
 	if migrationDir == "" {
 		migrationDir = "internal/pkg/database/migration"
 	}
 
 	migrationPath := filepath.Join(pwd, migrationDir)
 
-	// Cache directory for compiled binary
-	pathHash := CalculatePathHash(pwd)
+	// Cache directory for compiled binary, per migration directory of the connections
+	pathHash := CalculatePathHash(migrationPath)
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 	cacheDir := filepath.Join(homeDir, ".bingo", "migrator", fmt.Sprintf("%s_%s", projectName, pathHash))
 
+	// Schema dump of a named connection, the default connection uses the default directory
+	var schemaDir string
+	if name := config.Cfg.GetConnectionName(); name != "" {
+		schemaDir = filepath.Join(migrate.DefaultSchemaDir, name)
+	}
+
 	// Temp directory in project for compilation (to access internal packages)
 	tmpDir := filepath.Join(pwd, ".bingo_tmp")
 
@@ -83,8 +93,9 @@ func NewRunner(verbose, rebuild bool) (*Runner, error) {
 		userModule:    userModule,
 		migrationDir:  migrationDir,
 		migrationPath: migrationPath,
-		dbOptions:     config.Cfg.MysqlOptions,
+		dbOptions:     config.Cfg.GetDatabaseOptions(),
 		migrateTable:  config.Cfg.GetMigrateTable(),
+		schemaDir:     schemaDir,
 		cacheDir:      cacheDir,
 		tmpDir:        tmpDir,
 		verbose:       verbose,
@@ -92,9 +103,24 @@ func NewRunner(verbose, rebuild bool) (*Runner, error) {
 	}, nil
 }
 
-// Run executes the migration command.
-func (r *Runner) Run(command string) error {
+// MigrationDir returns the migration directory relative to the project directory.
+func (r *Runner) MigrationDir() string {
+	return r.migrationDir
+}
+
+// Run executes the migration command, args are passed to the migrator binary, e.g. --output json.
+func (r *Runner) Run(command string, args ...string) error {
 	// Validate
+	if err := r.validateDB(); err != nil {
+		return err
+	}
+
+	return r.RunWithoutDB(command, args...)
+}
+
+// RunWithoutDB executes a migration command which doesn't connect to the database, e.g. lint,
+// so the database configuration isn't required.
+func (r *Runner) RunWithoutDB(command string, args ...string) error {
 	if err := r.validate(); err != nil {
 		return err
 	}
@@ -112,7 +138,7 @@ func (r *Runner) Run(command string) error {
 	}
 
 	// Execute
-	return r.execute(command)
+	return r.execute(command, args...)
 }
 
 func (r *Runner) validate() error {
@@ -121,11 +147,19 @@ func (r *Runner) validate() error {
 		return fmt.Errorf("migration directory not found: %s", r.migrationPath)
 	}
 
+	return nil
+}
+
+func (r *Runner) validateDB() error {
 	// Check database config
 	if r.dbOptions == nil {
 		return fmt.Errorf("database configuration not found in .bingo.yaml")
 	}
 
+	if err := r.dbOptions.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -144,7 +178,7 @@ func (r *Runner) needsBuild() (bool, error) {
 		return true, nil
 	}
 
-	newChecksum, err := CalculateChecksum(r.migrationPath)
+	newChecksum, err := r.checksum()
 	if err != nil {
 		return true, nil
 	}
@@ -153,7 +187,8 @@ func (r *Runner) needsBuild() (bool, error) {
 }
 
 func (r *Runner) build() error {
-	fmt.Println("Compiling migrations...")
+	// Progress goes to stderr to keep the output of commands like status --output json parseable.
+	fmt.Fprintln(os.Stderr, "Compiling migrations...")
 
 	// Create directories
 	if err := os.MkdirAll(r.cacheDir, 0755); err != nil {
@@ -166,8 +201,14 @@ func (r *Runner) build() error {
 	// Ensure cleanup of temp directory
 	defer os.RemoveAll(r.tmpDir)
 
+	// Copy SQL migrations next to main.go, which embeds them
+	sqlFiles, err := r.copySQLMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to copy SQL migrations: %w", err)
+	}
+
 	// Generate main.go in temp directory
-	if err := r.generateMainGo(); err != nil {
+	if err := r.generateMainGo(len(sqlFiles) > 0); err != nil {
 		return fmt.Errorf("failed to generate main.go: %w", err)
 	}
 
@@ -179,7 +220,7 @@ func (r *Runner) build() error {
 	}
 
 	// Save checksum
-	checksum, err := CalculateChecksum(r.migrationPath)
+	checksum, err := r.checksum()
 	if err != nil {
 		return fmt.Errorf("failed to calculate checksum: %w", err)
 	}
@@ -188,11 +229,37 @@ func (r *Runner) build() error {
 		return fmt.Errorf("failed to save checksum: %w", err)
 	}
 
-	fmt.Println("Compilation successful.")
+	fmt.Fprintln(os.Stderr, "Compilation successful.")
 	return nil
 }
 
-func (r *Runner) generateMainGo() error {
+// copySQLMigrations copies the SQL migrations of the migration directory to the sql directory of the temp
+// directory, go:embed can't reach files outside of the package directory.
+func (r *Runner) copySQLMigrations() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(r.migrationPath, "*.sql"))
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	sqlDir := filepath.Join(r.tmpDir, "sql")
+	if err := os.MkdirAll(sqlDir, 0755); err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(sqlDir, filepath.Base(file)), content, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func (r *Runner) generateMainGo(embedSQL bool) error {
 	tplContent, err := tplFS.ReadFile("tpl/main.go.tpl")
 	if err != nil {
 		return err
@@ -203,8 +270,9 @@ func (r *Runner) generateMainGo() error {
 		return err
 	}
 
-	data := map[string]string{
+	data := map[string]any{
 		"MigrationImport": r.userModule + "/" + r.migrationDir,
+		"EmbedSQL":        embedSQL,
 	}
 
 	var buf bytes.Buffer
@@ -239,15 +307,24 @@ func (r *Runner) runBuildCommand(outputPath string) error {
 	return nil
 }
 
-func (r *Runner) execute(command string) error {
+func (r *Runner) execute(command string, args ...string) error {
 	binaryPath := r.binaryPath()
 
-	cmd := exec.Command(binaryPath, command,
-		"--host", r.dbOptions.Host,
-		"--username", r.dbOptions.Username,
-		"--password", r.dbOptions.Password,
-		"--database", r.dbOptions.Database,
-	)
+	if r.dbOptions != nil {
+		args = append([]string{
+			"--driver", r.dbOptions.GetDriver(),
+			"--host", r.dbOptions.Host,
+			"--username", r.dbOptions.Username,
+			"--password", r.dbOptions.Password,
+			"--database", r.dbOptions.Database,
+			"--sslmode", r.dbOptions.SSLMode,
+		}, args...)
+	}
+	if r.schemaDir != "" {
+		args = append([]string{"--schema-dir", r.schemaDir}, args...)
+	}
+	args = append([]string{command}, args...)
+	cmd := exec.Command(binaryPath, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -257,6 +334,26 @@ func (r *Runner) execute(command string) error {
 	return cmd.Run()
 }
 
+// checksum returns the checksum of the migrations and the main.go template,
+// so the binary is rebuilt when either of them changes.
+func (r *Runner) checksum() (string, error) {
+	checksum, err := CalculateChecksum(r.migrationPath)
+	if err != nil {
+		return "", err
+	}
+
+	tplContent, err := tplFS.ReadFile("tpl/main.go.tpl")
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(checksum))
+	h.Write(tplContent)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func (r *Runner) binaryPath() string {
 	name := "migrator"
 	if runtime.GOOS == "windows" {
@@ -2,8 +2,12 @@
 package main
 
 import (
+{{- if .EmbedSQL}}
+	"embed"
+{{- end}}
 	"fmt"
 	"os"
+	"time"
 
 	_ "{{.MigrationImport}}"
 
@@ -12,52 +16,127 @@ import (
 	"github.com/spf13/pflag"
 )
 
+{{- if .EmbedSQL}}
+
+//go:embed sql/*.sql
+var sqlMigrations embed.FS
+{{- end}}
+
 func main() {
 	var (
+		driver   string
 		host     string
 		username string
 		password string
 		database string
+		sslMode  string
+		output   string
+		step     int
+		batch    int
+		pretend  bool
+		timeout  time.Duration
+
+		allowOutOfOrder bool
+		pruneDir        string
+		schemaDir       string
 	)
 
+	pflag.StringVar(&driver, "driver", "mysql", "database driver: mysql, postgres, sqlite")
 	pflag.StringVar(&host, "host", "", "database host")
 	pflag.StringVar(&username, "username", "", "database username")
 	pflag.StringVar(&password, "password", "", "database password")
-	pflag.StringVar(&database, "database", "", "database name")
+	pflag.StringVar(&database, "database", "", "database name, or file path for sqlite")
+	pflag.StringVar(&sslMode, "sslmode", "", "postgres ssl mode")
+	pflag.StringVar(&output, "output", "table", "status output format: table, json")
+	pflag.IntVar(&step, "step", 0, "number of migrations to run or roll back")
+	pflag.IntVar(&batch, "batch", 0, "batch of migrations to roll back")
+	pflag.BoolVar(&pretend, "pretend", false, "print the SQL of the migrations without running them")
+	pflag.DurationVar(&timeout, "lock-timeout", 0, "how long to wait for another process running migrations")
+	pflag.BoolVar(&allowOutOfOrder, "allow-out-of-order", false, "run pending migrations older than the last ran migration")
+	pflag.StringVar(&pruneDir, "prune-dir", "", "delete the files of the migrations squashed by dump in this directory")
+	pflag.StringVar(&schemaDir, "schema-dir", "", "directory of the schema dump, default database/schema")
 	pflag.Parse()
 
 	args := pflag.Args()
 	if len(args) < 1 {
-		fmt.Println("Usage: migrator <up|rollback|reset|refresh|fresh> --host=<host> --username=<user> --password=<pass> --database=<db>")
+		fmt.Fprintln(os.Stderr, "Usage: migrator <up|rollback|reset|refresh|fresh|status|goto NAME|dump|lint> --driver=<driver> --host=<host> --username=<user> --password=<pass> --database=<db>")
 		os.Exit(1)
 	}
+{{- if .EmbedSQL}}
+
+	if err := migrate.AddSQL(sqlMigrations, "sql"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+{{- end}}
+
+	// lint checks the migrations without a database.
+	if args[0] == "lint" {
+		if err := migrate.PrintLint(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 
-	dbConn, err := db.NewMySQL(&db.MySQLOptions{
+		return
+	}
+
+	dbConn, err := db.NewDB(&db.Options{
+		Driver:   driver,
 		Host:     host,
 		Username: username,
 		Password: password,
 		Database: database,
+		SSLMode:  sslMode,
 	})
 	if err != nil {
-		fmt.Printf("Failed to connect database: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to connect database: %v\n", err)
 		os.Exit(1)
 	}
 
 	migrator := migrate.NewMigrator(dbConn)
+	migrator.Pretend = pretend
+	migrator.LockTimeout = timeout
+	migrator.AllowOutOfOrder = allowOutOfOrder
+	migrator.SchemaDir = schemaDir
 
 	switch args[0] {
 	case "up":
-		migrator.Up()
+		err = migrator.UpSteps(step)
 	case "rollback":
-		migrator.Rollback()
+		switch {
+		case batch > 0:
+			err = migrator.RollbackBatch(batch)
+		case step > 0:
+			err = migrator.RollbackSteps(step)
+		default:
+			err = migrator.Rollback()
+		}
 	case "reset":
-		migrator.Reset()
+		err = migrator.Reset()
 	case "refresh":
-		migrator.Refresh()
+		err = migrator.Refresh()
 	case "fresh":
-		migrator.Fresh()
+		err = migrator.Fresh()
+	case "status":
+		err = migrator.PrintStatus(os.Stdout, output)
+	case "goto":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: migrator goto NAME")
+			os.Exit(1)
+		}
+		err = migrator.Goto(args[1])
+	case "dump":
+		var names []string
+		if names, err = migrator.Dump(); err == nil && pruneDir != "" {
+			err = migrate.PruneMigrationFiles(pruneDir, names)
+		}
 	default:
-		fmt.Printf("Unknown command: %s\n", args[0])
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[0])
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,530 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bingo-project/component-base/cli/console"
+	"github.com/mgutz/ansi"
+	"gorm.io/gorm"
+)
+
+// DefaultSchemaDir is the directory of the schema dumps, relative to the working directory.
+const DefaultSchemaDir = "database/schema"
+
+var (
+	// schemaMigrationReg matches the statements of a schema dump recording a squashed migration.
+	schemaMigrationReg = regexp.MustCompile(`^INSERT INTO \S+ \(\S+, \S+\) VALUES \('((?:[^']|'')*)', (\d+)\)$`)
+
+	// mysqlAutoIncrementReg matches the AUTO_INCREMENT counter of SHOW CREATE TABLE.
+	mysqlAutoIncrementReg = regexp.MustCompile(` AUTO_INCREMENT=\d+`)
+
+	// mysqlDefinerReg matches the DEFINER clause of views and triggers, which is bound to a database user.
+	mysqlDefinerReg = regexp.MustCompile(` DEFINER=\S+`)
+)
+
+const schemaHeader = `-- Schema dump generated by bingo migrate dump.
+-- An empty database loads this file instead of running the migrations recorded at the end of it.
+
+`
+
+// schemaDump is a parsed schema dump.
+type schemaDump struct {
+	Statements []string
+	Squashed   []Migration
+}
+
+// SchemaPath returns the path of the schema dump of the database dialect, e.g. database/schema/mysql-schema.sql.
+func (migrator *Migrator) SchemaPath() string {
+	dir := migrator.SchemaDir
+	if dir == "" {
+		dir = DefaultSchemaDir
+	}
+
+	return filepath.Join(dir, migrator.DB.Dialector.Name()+"-schema.sql")
+}
+
+// Dump writes the schema of the database followed by the ran migrations to SchemaPath. These migrations
+// are squashed: an empty database loads the dump instead of running them. It returns their names.
+func (migrator *Migrator) Dump() ([]string, error) {
+	statements, err := schemaStatements(migrator.DB)
+	if err != nil {
+		return nil, fmt.Errorf("dump schema: %w", err)
+	}
+
+	var migrations []Migration
+	if err := migrator.DB.Order("id").Find(&migrations).Error; err != nil {
+		return nil, err
+	}
+
+	var buf strings.Builder
+	buf.WriteString(schemaHeader)
+	for _, statement := range statements {
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+
+		// Statements containing semicolons, e.g. trigger bodies, are executed as a whole.
+		if strings.Contains(statement, ";") {
+			fmt.Fprintf(&buf, "%s\n%s;\n%s\n\n", sqlStatementBegin, statement, sqlStatementEnd)
+			continue
+		}
+
+		fmt.Fprintf(&buf, "%s;\n\n", statement)
+	}
+
+	names := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		fmt.Fprintf(&buf, "%s ('%s', %d);\n", insertMigrationSQL(migrator.DB),
+			strings.ReplaceAll(migration.Migration, "'", "''"), migration.Batch)
+		names = append(names, migration.Migration)
+	}
+
+	path := migrator.SchemaPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(buf.String()), 0644); err != nil {
+		return nil, err
+	}
+
+	console.Info(fmt.Sprintf("Schema dumped to %s, %d migrations squashed.", path, len(names)))
+
+	return names, nil
+}
+
+// readSchema parses the schema dump, it returns nil if there is none.
+func (migrator *Migrator) readSchema() (*schemaDump, error) {
+	path := migrator.SchemaPath()
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := SplitSQL(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	dump := &schemaDump{}
+	for _, statement := range statements {
+		match := schemaMigrationReg.FindStringSubmatch(statement)
+		if match == nil {
+			dump.Statements = append(dump.Statements, statement)
+			continue
+		}
+
+		batch, _ := strconv.Atoi(match[2])
+		dump.Squashed = append(dump.Squashed, Migration{Migration: strings.ReplaceAll(match[1], "''", "'"), Batch: batch})
+	}
+
+	return dump, nil
+}
+
+// loadSchema loads the schema dump into an empty database, so only the migrations newer than the dump run.
+// It returns the migrations squashed into the loaded dump.
+func (migrator *Migrator) loadSchema() ([]Migration, error) {
+	dump, err := migrator.readSchema()
+	if err != nil || dump == nil {
+		return nil, err
+	}
+
+	empty, err := migrator.emptyDatabase()
+	if err != nil || !empty {
+		return nil, err
+	}
+
+	return dump.Squashed, migrator.load(dump)
+}
+
+// load executes the statements of dump and records its squashed migrations. The statements run on a
+// single connection, so session settings like FOREIGN_KEY_CHECKS apply to all of them.
+func (migrator *Migrator) load(dump *schemaDump) error {
+	path := migrator.SchemaPath()
+	load := func(db *gorm.DB) error {
+		for _, statement := range dump.Statements {
+			if err := db.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		// Not created with the Migration model, so pretending records the statement instead of running it.
+		for _, squashed := range dump.Squashed {
+			if err := db.Exec(insertMigrationSQL(db)+" (?, ?)", squashed.Migration, squashed.Batch).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if migrator.Pretend {
+		return migrator.pretend(path, "load", load)
+	}
+
+	fmt.Printf("%s %s\n", ansi.Color("Loading:", "yellow"), path)
+
+	err := migrator.DB.Connection(func(tx *gorm.DB) error {
+		if migrator.transactional() {
+			return tx.Transaction(load)
+		}
+
+		return load(tx)
+	})
+	if err != nil {
+		fmt.Printf("%s  %s\n", ansi.Color("Failed:", "red"), path)
+
+		return fmt.Errorf("load %s: %w", path, err)
+	}
+
+	fmt.Printf("%s  %s\n", ansi.Color("Loaded:", "green"), path)
+
+	return nil
+}
+
+// insertMigrationSQL returns the statement inserting a row into the migration table, without the values.
+func insertMigrationSQL(db *gorm.DB) string {
+	quote := db.Statement.Quote
+
+	return fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES", quote(migrationTableName), quote("migration"), quote("batch"))
+}
+
+// emptyDatabase returns true if no migration ran and there is no table besides the migration tables.
+func (migrator *Migrator) emptyDatabase() (bool, error) {
+	var count int64
+	if err := migrator.DB.Model(&Migration{}).Count(&count).Error; err != nil || count > 0 {
+		return false, err
+	}
+
+	tables, err := migrator.Migrator.GetTables()
+	if err != nil {
+		return false, err
+	}
+
+	for _, table := range tables {
+		if !migrationTable(table) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// migrationTable returns true for the tables managed by the migrator and SQLite internal tables.
+func migrationTable(table string) bool {
+	return table == migrationTableName || table == (migrationLock{}).TableName() || strings.HasPrefix(table, "sqlite_")
+}
+
+// PruneMigrationFiles deletes the Go and SQL files in dir of the migrations named after names, e.g. the
+// migrations squashed by Dump. Migrations declared in a file named otherwise are left as is. When no Go
+// file is left, a file with the package clause is written so the migration package still compiles.
+func PruneMigrationFiles(dir string, names []string) error {
+	var packageName string
+	for _, name := range names {
+		pruned := false
+		for _, suffix := range []string{".go", SQLUpSuffix, SQLDownSuffix} {
+			file := filepath.Join(dir, name+suffix)
+			if _, err := os.Stat(file); err != nil {
+				continue
+			}
+
+			if suffix == ".go" && packageName == "" {
+				f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+				if err != nil {
+					return err
+				}
+				packageName = f.Name.Name
+			}
+
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+			pruned = true
+
+			fmt.Printf("%s  %s\n", ansi.Color("Pruned:", "green"), file)
+		}
+
+		if !pruned && GetMigrationFile(name).FileName != "" {
+			console.Warn(fmt.Sprintf("Migration %s isn't declared in %s.go, remove it manually.", name, name))
+		}
+	}
+
+	if packageName == "" {
+		return nil
+	}
+
+	goFiles, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil || len(goFiles) > 0 {
+		return err
+	}
+
+	content := fmt.Sprintf("// Package %s contains the database migrations newer than the schema dump.\npackage %s\n",
+		packageName, packageName)
+
+	return os.WriteFile(filepath.Join(dir, "doc.go"), []byte(content), 0644)
+}
+
+// schemaStatements returns the statements creating the tables, views and triggers of the database,
+// the migration tables excluded.
+func schemaStatements(db *gorm.DB) ([]string, error) {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return mysqlSchema(db)
+	case "postgres":
+		return postgresSchema(db)
+	case "sqlite":
+		return sqliteSchema(db)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %s", db.Dialector.Name())
+	}
+}
+
+func sqliteSchema(db *gorm.DB) ([]string, error) {
+	rows, err := queryRows(db, `SELECT tbl_name, sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		ORDER BY CASE type WHEN 'table' THEN 1 WHEN 'index' THEN 2 WHEN 'view' THEN 3 ELSE 4 END, rowid`)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, row := range rows {
+		if !migrationTable(row[0]) {
+			statements = append(statements, row[1])
+		}
+	}
+
+	return statements, nil
+}
+
+func mysqlSchema(db *gorm.DB) ([]string, error) {
+	tables, err := queryRows(db, "SHOW FULL TABLES")
+	if err != nil {
+		return nil, err
+	}
+
+	// Tables are created in name order, foreign keys are checked once all of them exist.
+	statements := []string{"SET FOREIGN_KEY_CHECKS = 0"}
+	var views []string
+	for _, table := range tables {
+		name, tableType := table[0], table[1]
+		if tableType == "VIEW" {
+			views = append(views, name)
+			continue
+		}
+		if migrationTable(name) {
+			continue
+		}
+
+		rows, err := queryRows(db, "SHOW CREATE TABLE "+db.Statement.Quote(name))
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, mysqlAutoIncrementReg.ReplaceAllString(rows[0][1], ""))
+	}
+
+	for _, view := range views {
+		rows, err := queryRows(db, "SHOW CREATE VIEW "+db.Statement.Quote(view))
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, mysqlDefinerReg.ReplaceAllString(rows[0][1], ""))
+	}
+
+	triggers, err := queryRows(db, "SHOW TRIGGERS")
+	if err != nil {
+		return nil, err
+	}
+	for _, trigger := range triggers {
+		rows, err := queryRows(db, "SHOW CREATE TRIGGER "+db.Statement.Quote(trigger[0]))
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, mysqlDefinerReg.ReplaceAllString(rows[0][2], ""))
+	}
+
+	return append(statements, "SET FOREIGN_KEY_CHECKS = 1"), nil
+}
+
+// pgRelation selects the oid of the table of the current schema passed as argument.
+const pgRelation = `(SELECT c.oid FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = CURRENT_SCHEMA() AND c.relname = ?)`
+
+// pgSerialTypes maps the integer types to the serial type creating their sequence.
+var pgSerialTypes = map[string]string{
+	"smallint": "smallserial",
+	"integer":  "serial",
+	"bigint":   "bigserial",
+}
+
+func postgresSchema(db *gorm.DB) ([]string, error) {
+	var schema string
+	if err := db.Raw("SELECT CURRENT_SCHEMA()").Scan(&schema).Error; err != nil {
+		return nil, err
+	}
+	unqualify := func(statement, keyword string) string {
+		return strings.Replace(statement, keyword+" "+schema+".", keyword+" ", 1)
+	}
+
+	var statements []string
+
+	// Functions first, column defaults and triggers may call them. Functions of extensions are skipped.
+	functions, err := queryRows(db, `SELECT pg_get_functiondef(p.oid) FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = CURRENT_SCHEMA() AND p.prokind = 'f'
+		AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')
+		ORDER BY p.proname`)
+	if err != nil {
+		return nil, err
+	}
+	for _, function := range functions {
+		statements = append(statements, unqualify(function[0], "FUNCTION"))
+	}
+
+	tables, err := queryRows(db, `SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = CURRENT_SCHEMA() AND c.relkind = 'r' ORDER BY c.relname`)
+	if err != nil {
+		return nil, err
+	}
+
+	// Foreign keys are added once all tables exist.
+	var indexes, foreignKeys []string
+	for _, table := range tables {
+		name := table[0]
+		if migrationTable(name) {
+			continue
+		}
+
+		columns, err := queryRows(db, `SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+			COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), a.attidentity::text
+			FROM pg_attribute a LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+			WHERE a.attrelid = `+pgRelation+` AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum`, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var definitions []string
+		for _, column := range columns {
+			definitions = append(definitions, pgColumnDefinition(db, column))
+		}
+
+		constraints, err := queryRows(db, `SELECT conname, contype::text, pg_get_constraintdef(oid) FROM pg_constraint
+			WHERE conrelid = `+pgRelation+` ORDER BY contype DESC, conname`, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, constraint := range constraints {
+			definition := "CONSTRAINT " + db.Statement.Quote(constraint[0]) + " " + constraint[2]
+			if constraint[1] == "f" {
+				foreignKeys = append(foreignKeys, "ALTER TABLE "+db.Statement.Quote(name)+" ADD "+definition)
+				continue
+			}
+			definitions = append(definitions, definition)
+		}
+
+		statements = append(statements, fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", db.Statement.Quote(name), strings.Join(definitions, ",\n  ")))
+
+		// Indexes backing constraints are created by the constraints.
+		rows, err := queryRows(db, `SELECT indexdef FROM pg_indexes WHERE schemaname = CURRENT_SCHEMA() AND tablename = ?
+			AND indexname NOT IN (SELECT conname FROM pg_constraint WHERE conrelid = `+pgRelation+`) ORDER BY indexname`, name, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			indexes = append(indexes, unqualify(row[0], "ON"))
+		}
+	}
+	statements = append(statements, indexes...)
+	statements = append(statements, foreignKeys...)
+
+	views, err := queryRows(db, `SELECT c.relname, pg_get_viewdef(c.oid) FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = CURRENT_SCHEMA() AND c.relkind = 'v' ORDER BY c.oid`)
+	if err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		statements = append(statements, "CREATE VIEW "+db.Statement.Quote(view[0])+" AS\n"+strings.TrimSuffix(strings.TrimSpace(view[1]), ";"))
+	}
+
+	triggers, err := queryRows(db, `SELECT pg_get_triggerdef(t.oid) FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = CURRENT_SCHEMA() AND NOT t.tgisinternal ORDER BY t.tgname`)
+	if err != nil {
+		return nil, err
+	}
+	for _, trigger := range triggers {
+		statements = append(statements, unqualify(trigger[0], "ON"))
+	}
+
+	return statements, nil
+}
+
+// pgColumnDefinition returns the definition of column, a row of name, type, not null, default and identity.
+// Integer columns defaulting to a sequence are declared serial, which creates the sequence.
+func pgColumnDefinition(db *gorm.DB, column []string) string {
+	name, typ, notNull, def, identity := column[0], column[1], column[2], column[3], column[4]
+	if serial, ok := pgSerialTypes[typ]; ok && strings.HasPrefix(def, "nextval(") {
+		typ, def = serial, ""
+	}
+
+	definition := db.Statement.Quote(name) + " " + typ
+	switch identity {
+	case "a":
+		definition += " GENERATED ALWAYS AS IDENTITY"
+	case "d":
+		definition += " GENERATED BY DEFAULT AS IDENTITY"
+	}
+	if def != "" {
+		definition += " DEFAULT " + def
+	}
+	if notNull == "true" {
+		definition += " NOT NULL"
+	}
+
+	return definition
+}
+
+// queryRows returns the rows of query as strings, NULL is returned as an empty string.
+func queryRows(db *gorm.DB, query string, args ...any) ([][]string, error) {
+	rows, err := db.Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = value.String
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,128 @@
+// ABOUTME: Tests for the schema dump squashing ran migrations
+// ABOUTME: Verifies dumping, loading the dump into an empty database and pruning squashed migration files
+
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestDump_LoadsIntoEmptyDatabase(t *testing.T) {
+	setupMigrationFiles(t)
+	Add("2024_01_01_000000_create_posts", func(db *gorm.DB) error {
+		return db.Exec("CREATE TABLE posts (id integer primary key, title varchar(255) DEFAULT 'it''s')").Error
+	}, nil)
+	Add("2024_01_02_000000_create_post_titles", func(db *gorm.DB) error {
+		return db.Exec("CREATE VIEW post_titles AS SELECT id, title FROM posts").Error
+	}, nil)
+
+	dir := t.TempDir()
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+	migrator.SchemaDir = dir
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+
+	names, err := migrator.Dump()
+	if err != nil {
+		t.Fatalf("Dump() failed: %v", err)
+	}
+	if len(names) != 2 {
+		t.Errorf("squashed = %v, want both migrations", names)
+	}
+	if path := migrator.SchemaPath(); path != filepath.Join(dir, "sqlite-schema.sql") {
+		t.Errorf("SchemaPath() = %s", path)
+	}
+
+	// A migration added after the dump runs after loading it.
+	Add("2024_01_03_000000_create_tags", func(db *gorm.DB) error {
+		return db.Exec("CREATE TABLE tags (id integer)").Error
+	}, nil)
+
+	empty := setupTestDB(t)
+	loader := NewMigrator(empty)
+	loader.SchemaDir = dir
+	if err := loader.Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+
+	for _, table := range []string{"posts", "tags"} {
+		if !empty.Migrator().HasTable(table) {
+			t.Errorf("expected table %s", table)
+		}
+	}
+	var title string
+	empty.Exec("INSERT INTO posts (id) VALUES (1)")
+	empty.Raw("SELECT title FROM post_titles").Scan(&title)
+	if title != "it's" {
+		t.Errorf("post_titles title = %q, want the view and default loaded", title)
+	}
+
+	var batches []int
+	empty.Model(&Migration{}).Order("id").Pluck("batch", &batches)
+	if got := strings.Join(ranMigrations(empty), ","); got != strings.Join(append(names, "2024_01_03_000000_create_tags"), ",") {
+		t.Errorf("ran migrations = %s", got)
+	}
+	if len(batches) != 3 || batches[0] != 1 || batches[2] != 2 {
+		t.Errorf("batches = %v, want squashed batches kept and a new batch", batches)
+	}
+}
+
+func TestStatus_MarksSquashedMigrations(t *testing.T) {
+	setupMigrationFiles(t, "2024_01_01_000000_first")
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+	migrator.SchemaDir = t.TempDir()
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+	db.Exec("INSERT INTO bingo_migration (migration, batch) VALUES ('2023_01_01_000000_deleted', 1)")
+
+	if _, err := migrator.Dump(); err != nil {
+		t.Fatalf("Dump() failed: %v", err)
+	}
+	setupMigrationFiles(t)
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+	if len(statuses) != 2 || !statuses[0].Squashed || statuses[0].Orphaned || !statuses[1].Squashed {
+		t.Errorf("statuses = %+v, want both migrations squashed", statuses)
+	}
+}
+
+func TestPruneMigrationFiles(t *testing.T) {
+	setupMigrationFiles(t, "2024_01_03_000000_shared")
+	dir := t.TempDir()
+	files := map[string]string{
+		"2024_01_01_000000_create_posts.go":      "package migration\n",
+		"2024_01_02_000000_create_view.up.sql":   "CREATE VIEW v AS SELECT 1;\n",
+		"2024_01_02_000000_create_view.down.sql": "DROP VIEW v;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := []string{"2024_01_01_000000_create_posts", "2024_01_02_000000_create_view", "2024_01_03_000000_shared"}
+	if err := PruneMigrationFiles(dir, names); err != nil {
+		t.Fatalf("PruneMigrationFiles() failed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "doc.go" {
+		t.Fatalf("files = %v, want only doc.go", entries)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "doc.go"))
+	if !strings.Contains(string(content), "package migration\n") {
+		t.Errorf("doc.go = %q, want the package clause", content)
+	}
+}
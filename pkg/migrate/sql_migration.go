@@ -0,0 +1,239 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SQL migration file suffixes, e.g. 2024_01_02_150405_create_posts_view.up.sql.
+const (
+	SQLUpSuffix   = ".up.sql"
+	SQLDownSuffix = ".down.sql"
+)
+
+// Markers around a statement containing semicolons which must be executed as a whole, e.g. a trigger body.
+const (
+	sqlStatementBegin = "-- bingo:statement-begin"
+	sqlStatementEnd   = "-- bingo:statement-end"
+)
+
+// AddSQL registers the *.up.sql and *.down.sql pairs in dir of fsys. The migration name is the file name
+// without the suffix, SQL migrations are ordered by name together with the migrations registered by Add.
+// A down file is optional, an up file without down file can't be reverted.
+func AddSQL(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	ups := make(map[string]string)
+	downs := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		file := path.Join(dir, entry.Name())
+		switch name := entry.Name(); {
+		case strings.HasSuffix(name, SQLUpSuffix):
+			ups[strings.TrimSuffix(name, SQLUpSuffix)] = file
+		case strings.HasSuffix(name, SQLDownSuffix):
+			downs[strings.TrimSuffix(name, SQLDownSuffix)] = file
+		}
+	}
+
+	for name := range downs {
+		if _, ok := ups[name]; !ok {
+			return fmt.Errorf("migration %s has no %s file", name, SQLUpSuffix)
+		}
+	}
+
+	names := make([]string, 0, len(ups))
+	for name := range ups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		up, err := sqlMigrationFunc(fsys, ups[name])
+		if err != nil {
+			return err
+		}
+
+		var down migrationFunc
+		if file, ok := downs[name]; ok {
+			if down, err = sqlMigrationFunc(fsys, file); err != nil {
+				return err
+			}
+		}
+
+		migrationFiles = append(migrationFiles, MigrationFile{FileName: name, Up: up, Down: down})
+	}
+
+	return nil
+}
+
+// sqlMigrationFunc returns the function executing the statements of file.
+func sqlMigrationFunc(fsys fs.FS, file string) (migrationFunc, error) {
+	content, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := SplitSQL(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return func(db *gorm.DB) error {
+		for _, statement := range statements {
+			if err := db.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+// SplitSQL splits script into statements on semicolons outside of quotes, comments and PostgreSQL
+// dollar-quoted strings. Lines between the statement-begin and statement-end markers are one statement.
+func SplitSQL(script string) ([]string, error) {
+	var (
+		statements []string
+		current    strings.Builder
+		quote      string // The quote, comment or dollar tag the scanner is in
+		block      bool   // Between statement markers
+	)
+
+	flush := func() {
+		if statement := trimComments(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(script, "\n") {
+		if quote == "" {
+			switch strings.TrimSpace(line) {
+			case sqlStatementBegin:
+				flush()
+				block = true
+
+				continue
+			case sqlStatementEnd:
+				if !block {
+					return nil, fmt.Errorf("%s without %s", sqlStatementEnd, sqlStatementBegin)
+				}
+				statement := strings.TrimSpace(current.String())
+				current.Reset()
+				current.WriteString(strings.TrimSuffix(statement, ";"))
+				flush()
+				block = false
+
+				continue
+			}
+		}
+
+		if block {
+			current.WriteString(line)
+			continue
+		}
+
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			rest := line[i:]
+
+			switch {
+			case quote == "--":
+				// Line comments end with the line.
+			case quote == "/*":
+				if strings.HasPrefix(rest, "*/") {
+					current.WriteString("*/")
+					i++
+					quote = ""
+
+					continue
+				}
+			case quote != "":
+				// MySQL escapes quotes with a backslash.
+				if c == '\\' && len(quote) == 1 && i+1 < len(line) {
+					current.WriteString(line[i : i+2])
+					i++
+
+					continue
+				}
+				if strings.HasPrefix(rest, quote) {
+					current.WriteString(quote)
+					i += len(quote) - 1
+					quote = ""
+
+					continue
+				}
+			case strings.HasPrefix(rest, "--"):
+				quote = "--"
+			case strings.HasPrefix(rest, "/*"):
+				quote = "/*"
+			case c == '\'' || c == '"' || c == '`':
+				quote = string(c)
+			case c == '$':
+				if tag := dollarTag(rest); tag != "" {
+					current.WriteString(tag)
+					i += len(tag) - 1
+					quote = tag
+
+					continue
+				}
+			case c == ';':
+				flush()
+
+				continue
+			}
+
+			current.WriteByte(c)
+		}
+
+		if quote == "--" {
+			quote = ""
+		}
+	}
+
+	if block {
+		return nil, fmt.Errorf("%s without %s", sqlStatementBegin, sqlStatementEnd)
+	}
+	flush()
+
+	return statements, nil
+}
+
+// dollarTag returns the PostgreSQL dollar quote tag s starts with, e.g. $$ or $body$.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// trimComments trims the whitespace and the comment lines preceding statement.
+func trimComments(statement string) string {
+	for {
+		statement = strings.TrimSpace(statement)
+		if !strings.HasPrefix(statement, "--") {
+			return statement
+		}
+
+		_, statement, _ = strings.Cut(statement, "\n")
+	}
+}
@@ -0,0 +1,104 @@
+// ABOUTME: Tests for plain SQL migration files
+// ABOUTME: Verifies statement splitting, ordering among Go migrations and running up and down files
+
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitSQL(t *testing.T) {
+	script := `-- Create the view
+CREATE VIEW v AS SELECT 1;
+INSERT INTO t (a, b) VALUES ('a;b', 'it''s; "x"'); /* c; */
+CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.a := 'x;'; RETURN NEW; END; $body$ LANGUAGE plpgsql;
+
+-- bingo:statement-begin
+CREATE TRIGGER tr AFTER INSERT ON t
+BEGIN
+  INSERT INTO u VALUES (1);
+END;
+-- bingo:statement-end
+-- trailing comment
+`
+
+	statements, err := SplitSQL(script)
+	if err != nil {
+		t.Fatalf("SplitSQL() failed: %v", err)
+	}
+
+	want := []string{
+		"CREATE VIEW v AS SELECT 1",
+		`INSERT INTO t (a, b) VALUES ('a;b', 'it''s; "x"')`,
+		"/* c; */\nCREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.a := 'x;'; RETURN NEW; END; $body$ LANGUAGE plpgsql",
+		"CREATE TRIGGER tr AFTER INSERT ON t\nBEGIN\n  INSERT INTO u VALUES (1);\nEND",
+	}
+	if len(statements) != len(want) {
+		t.Fatalf("SplitSQL() = %q, want %q", statements, want)
+	}
+	for i := range want {
+		if statements[i] != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, statements[i], want[i])
+		}
+	}
+
+	if _, err := SplitSQL("-- bingo:statement-begin\nSELECT 1;\n"); err == nil {
+		t.Error("SplitSQL() should fail on an unterminated statement block")
+	}
+}
+
+func TestAddSQL_OrdersAmongGoMigrationsAndRuns(t *testing.T) {
+	setupMigrationFiles(t, "2024_01_01_000000_first", "2024_01_03_000000_third")
+
+	fsys := fstest.MapFS{
+		"sql/2024_01_02_000000_create_notes.up.sql":   {Data: []byte("CREATE TABLE notes (id integer);\nINSERT INTO notes VALUES (1);\n")},
+		"sql/2024_01_02_000000_create_notes.down.sql": {Data: []byte("DROP TABLE notes;\n")},
+		"sql/2024_01_04_000000_no_down.up.sql":        {Data: []byte("CREATE TABLE other (id integer);\n")},
+		"sql/README.md":                               {Data: []byte("ignored")},
+	}
+	if err := AddSQL(fsys, "sql"); err != nil {
+		t.Fatalf("AddSQL() failed: %v", err)
+	}
+
+	var names []string
+	for _, migrationFile := range sortedMigrationFiles() {
+		names = append(names, migrationFile.FileName)
+	}
+	want := "2024_01_01_000000_first,2024_01_02_000000_create_notes,2024_01_03_000000_third,2024_01_04_000000_no_down"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("migrations = %s, want %s", got, want)
+	}
+
+	db := setupTestDB(t)
+	migrator := NewMigrator(db)
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() failed: %v", err)
+	}
+
+	var count int64
+	db.Table("notes").Count(&count)
+	if count != 1 {
+		t.Errorf("notes rows = %d, want 1", count)
+	}
+
+	if err := migrator.Reset(); err != nil {
+		t.Fatalf("Reset() failed: %v", err)
+	}
+	if db.Migrator().HasTable("notes") {
+		t.Error("expected notes table dropped by the down file")
+	}
+	if !db.Migrator().HasTable("other") {
+		t.Error("expected other table kept, its migration has no down file")
+	}
+}
+
+func TestAddSQL_RejectsDownWithoutUp(t *testing.T) {
+	setupMigrationFiles(t)
+
+	fsys := fstest.MapFS{"2024_01_02_000000_orphan.down.sql": {Data: []byte("DROP TABLE x;")}}
+	if err := AddSQL(fsys, "."); err == nil {
+		t.Error("AddSQL() should fail for a down file without up file")
+	}
+}
@@ -0,0 +1,118 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/mgutz/ansi"
+)
+
+// Output formats of PrintStatus.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// MigrationStatus is the state of a migration.
+type MigrationStatus struct {
+	Migration string `json:"migration"`
+	Ran       bool   `json:"ran"`
+	Batch     int    `json:"batch,omitempty"`
+
+	// Orphaned is set for migrations recorded in the migration table with no matching file.
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Squashed is set for migrations with no matching file squashed into the schema dump.
+	Squashed bool `json:"squashed,omitempty"`
+}
+
+// Status returns every registered migration ordered by name marked ran or pending, followed by the
+// squashed and orphaned ones.
+func (migrator *Migrator) Status() ([]MigrationStatus, error) {
+	var migrations []Migration
+	if err := migrator.DB.Order("id").Find(&migrations).Error; err != nil {
+		return nil, err
+	}
+
+	dump, err := migrator.readSchema()
+	if err != nil {
+		return nil, err
+	}
+	squashed := make(map[string]bool)
+	if dump != nil {
+		for _, migration := range dump.Squashed {
+			squashed[migration.Migration] = true
+		}
+	}
+
+	batches := make(map[string]int, len(migrations))
+	for _, migration := range migrations {
+		batches[migration.Migration] = migration.Batch
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrationFiles))
+	for _, migrationFile := range sortedMigrationFiles() {
+		batch, ran := batches[migrationFile.FileName]
+		statuses = append(statuses, MigrationStatus{Migration: migrationFile.FileName, Ran: ran, Batch: batch})
+	}
+
+	for _, migration := range migrations {
+		if GetMigrationFile(migration.Migration).FileName == "" {
+			statuses = append(statuses, MigrationStatus{
+				Migration: migration.Migration,
+				Ran:       true,
+				Batch:     migration.Batch,
+				Orphaned:  !squashed[migration.Migration],
+				Squashed:  squashed[migration.Migration],
+			})
+		}
+	}
+
+	return statuses, nil
+}
+
+// PrintStatus writes the migration status to w in output format, table or json.
+func (migrator *Migrator) PrintStatus(w io.Writer, output string) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(statuses)
+	case OutputTable, "":
+	default:
+		return fmt.Errorf("unsupported output format %q, use %s or %s", output, OutputTable, OutputJSON)
+	}
+
+	if len(statuses) == 0 {
+		_, err := fmt.Fprintln(w, "No migrations found.")
+
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MIGRATION\tBATCH\tSTATUS")
+	for _, status := range statuses {
+		batch, state := "", ansi.Color("Pending", "yellow")
+		if status.Ran {
+			batch, state = fmt.Sprint(status.Batch), ansi.Color("Ran", "green")
+		}
+		if status.Orphaned {
+			state = ansi.Color("Orphaned (file not found)", "red")
+		}
+		if status.Squashed {
+			state = ansi.Color("Squashed (schema dump)", "cyan")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", status.Migration, batch, state)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,485 @@
+package seed
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mgutz/ansi"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/bingo-project/bingoctl/pkg/db"
+)
+
+// Fixture reference syntax: a string value @users.admin is replaced by the primary key of the row
+// labeled admin in the users fixture. A leading @@ escapes a literal @.
+const (
+	fixtureRefPrefix = "@"
+	fixtureLabelKey  = "_name" // Label column of CSV fixtures
+	fixtureCSVNull   = `\N`    // NULL in CSV fixtures
+)
+
+// ErrFixtureCycle is returned by LoadFixtures when fixtures refer to each other.
+var ErrFixtureCycle = errors.New("fixture reference cycle")
+
+// fixtureExts are the supported fixture file extensions.
+var fixtureExts = []string{".yaml", ".yml", ".json", ".csv"}
+
+// FixtureOptions are the options of LoadFixtures.
+type FixtureOptions struct {
+	// Upsert updates the rows whose primary key exists instead of failing.
+	Upsert bool
+
+	// Truncate deletes the rows of the fixture tables before inserting.
+	Truncate bool
+}
+
+// fixture is a fixture file, its name is the file name without extension.
+type fixture struct {
+	name  string
+	file  string
+	table string
+	rows  []fixtureRow
+}
+
+// fixtureRow is a row of a fixture, label is empty for rows which can't be referenced.
+type fixtureRow struct {
+	label  string
+	values map[string]any
+}
+
+// LoadFixtures inserts the rows of the YAML, JSON and CSV fixture files in dir, one transaction per
+// file. With Truncate, the rows are deleted and all the files loaded in one transaction instead, so
+// nothing is deleted if a file fails. A fixture is named after its table or model, e.g. users.yaml or
+// User.json for the users table.
+//
+// YAML and JSON fixtures are a map of rows by label or a list of rows. CSV fixtures have a header
+// row, labels in an optional _name column and \N for NULL. Files are loaded after the fixtures
+// they refer to.
+func LoadFixtures(db *gorm.DB, dir string, opts FixtureOptions) error {
+	fixtures, err := readFixtures(db, dir)
+	if err != nil {
+		return err
+	}
+
+	fixtures, err = sortFixtures(fixtures)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]map[string]any, len(fixtures))
+	if !opts.Truncate {
+		for _, f := range fixtures {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return f.loadFile(tx, ids, opts)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Delete children before their parents.
+		for i := len(fixtures) - 1; i >= 0; i-- {
+			if err := tx.Exec("DELETE FROM " + tx.Statement.Quote(fixtures[i].table)).Error; err != nil {
+				return fmt.Errorf("%s: %w", fixtures[i].file, err)
+			}
+		}
+
+		for _, f := range fixtures {
+			if err := f.loadFile(tx, ids, opts); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// loadFile loads f reporting its progress, the error is prefixed with the file.
+func (f *fixture) loadFile(tx *gorm.DB, ids map[string]map[string]any, opts FixtureOptions) error {
+	fmt.Printf("%s %s\n", ansi.Color("Loading:", "yellow"), f.file)
+
+	ids[f.name] = make(map[string]any)
+	if err := f.load(tx, ids, opts); err != nil {
+		fmt.Printf("%s  %s\n", ansi.Color("Failed:", "red"), f.file)
+
+		return fmt.Errorf("%s: %w", f.file, err)
+	}
+
+	fmt.Printf("%s  %s (%d rows)\n", ansi.Color("Loaded:", "green"), f.file, len(f.rows))
+
+	return nil
+}
+
+// load inserts the rows of f, recording the primary keys of labeled rows in ids.
+func (f *fixture) load(tx *gorm.DB, ids map[string]map[string]any, opts FixtureOptions) error {
+	pk, err := primaryKey(tx, f.table)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range f.rows {
+		values := make(map[string]any, len(row.values))
+		for column, value := range row.values {
+			if values[column], err = resolveRef(value, ids); err != nil {
+				return err
+			}
+		}
+
+		query := tx.Table(f.table)
+		if _, ok := values[pk]; ok && opts.Upsert && pk != "" {
+			query = query.Clauses(upsertClause(pk, values))
+		}
+		if err := query.Create(values).Error; err != nil {
+			return err
+		}
+
+		if row.label == "" || pk == "" {
+			continue
+		}
+		if id, ok := values[pk]; ok {
+			ids[f.name][row.label] = id
+		} else if ids[f.name][row.label], err = lastInsertID(tx); err != nil {
+			return err
+		}
+	}
+
+	// Rows inserted with their primary key don't advance PostgreSQL sequences.
+	if pk == "" {
+		return nil
+	}
+
+	return db.ResetSequence(tx, f.table, pk)
+}
+
+// upsertClause updates the columns of values except the primary key on conflict.
+func upsertClause(pk string, values map[string]any) clause.OnConflict {
+	var columns []string
+	for column := range values {
+		if column != pk {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+
+	if len(columns) == 0 {
+		return clause.OnConflict{Columns: []clause.Column{{Name: pk}}, DoNothing: true}
+	}
+
+	return clause.OnConflict{Columns: []clause.Column{{Name: pk}}, DoUpdates: clause.AssignmentColumns(columns)}
+}
+
+// primaryKey returns the primary key column of table, empty if it has none or a composite one.
+func primaryKey(db *gorm.DB, table string) (string, error) {
+	columnTypes, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return "", err
+	}
+
+	var pks []string
+	for _, columnType := range columnTypes {
+		if isPK, ok := columnType.PrimaryKey(); ok && isPK {
+			pks = append(pks, columnType.Name())
+		}
+	}
+	if len(pks) != 1 {
+		return "", nil
+	}
+
+	return pks[0], nil
+}
+
+// lastInsertID returns the auto increment id of the row inserted last in the transaction.
+func lastInsertID(tx *gorm.DB) (any, error) {
+	var query string
+	switch tx.Dialector.Name() {
+	case "mysql":
+		query = "SELECT LAST_INSERT_ID()"
+	case "postgres":
+		query = "SELECT lastval()"
+	case "sqlite":
+		query = "SELECT last_insert_rowid()"
+	default:
+		return nil, fmt.Errorf("referring to rows without primary key isn't supported by %s", tx.Dialector.Name())
+	}
+
+	var id int64
+	err := tx.Raw(query).Scan(&id).Error
+
+	return id, err
+}
+
+// resolveRef returns the primary key value refers to, or value if it isn't a reference.
+func resolveRef(value any, ids map[string]map[string]any) (any, error) {
+	fixture, label, ok := parseRef(value)
+	if !ok {
+		if s, isString := value.(string); isString && strings.HasPrefix(s, fixtureRefPrefix+fixtureRefPrefix) {
+			return s[len(fixtureRefPrefix):], nil
+		}
+
+		return value, nil
+	}
+
+	id, ok := ids[fixture][label]
+	if !ok {
+		return nil, fmt.Errorf("row %s of fixture %s not found, rows must be labeled and defined before rows referring to them", label, fixture)
+	}
+
+	return id, nil
+}
+
+// parseRef returns the fixture and label value refers to.
+func parseRef(value any) (fixture, label string, ok bool) {
+	s, isString := value.(string)
+	if !isString || !strings.HasPrefix(s, fixtureRefPrefix) || strings.HasPrefix(s, fixtureRefPrefix+fixtureRefPrefix) {
+		return "", "", false
+	}
+
+	fixture, label, ok = strings.Cut(s[len(fixtureRefPrefix):], ".")
+
+	return fixture, label, ok && fixture != "" && label != ""
+}
+
+// sortFixtures returns fixtures sorted so that every fixture comes after the fixtures it refers to.
+func sortFixtures(fixtures []*fixture) ([]*fixture, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	byName := make(map[string]*fixture, len(fixtures))
+	for _, f := range fixtures {
+		byName[f.name] = f
+	}
+
+	var (
+		sorted []*fixture
+		path   []string
+		state  = make(map[string]int)
+	)
+
+	var visit func(f *fixture) error
+	visit = func(f *fixture) error {
+		switch state[f.name] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(slices.Clone(path[slices.Index(path, f.name):]), f.name)
+
+			return fmt.Errorf("%w: %s", ErrFixtureCycle, strings.Join(cycle, " -> "))
+		}
+
+		state[f.name] = visiting
+		path = append(path, f.name)
+
+		for _, name := range f.refs() {
+			// Rows may refer to rows defined before them in the same fixture.
+			if name == f.name {
+				continue
+			}
+
+			ref, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("%s: fixture %s not found", f.file, name)
+			}
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[f.name] = visited
+		sorted = append(sorted, f)
+
+		return nil
+	}
+
+	for _, f := range fixtures {
+		if err := visit(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// refs returns the names of the fixtures f refers to, sorted.
+func (f *fixture) refs() []string {
+	var names []string
+	for _, row := range f.rows {
+		for _, value := range row.values {
+			if name, _, ok := parseRef(value); ok && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// readFixtures reads the fixture files in dir sorted by name.
+func readFixtures(db *gorm.DB, dir string) ([]*fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*fixture
+	files := make(map[string]string)
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || !slices.Contains(fixtureExts, ext) {
+			continue
+		}
+
+		f := &fixture{name: strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())), file: filepath.Join(dir, entry.Name())}
+		if other, ok := files[f.name]; ok {
+			return nil, fmt.Errorf("fixture %s is defined by both %s and %s", f.name, other, f.file)
+		}
+		files[f.name] = f.file
+
+		if f.table, err = fixtureTable(db, f.name); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.file, err)
+		}
+
+		content, err := os.ReadFile(f.file)
+		if err != nil {
+			return nil, err
+		}
+		if ext == ".csv" {
+			f.rows, err = parseCSVFixture(content)
+		} else {
+			f.rows, err = parseYAMLFixture(content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.file, err)
+		}
+
+		fixtures = append(fixtures, f)
+	}
+
+	return fixtures, nil
+}
+
+// fixtureTable returns the table of the fixture named name, the table itself or the table of the
+// model name, e.g. users for User.
+func fixtureTable(db *gorm.DB, name string) (string, error) {
+	if db.Migrator().HasTable(name) {
+		return name, nil
+	}
+
+	table := schema.NamingStrategy{}.TableName(name)
+	if db.Migrator().HasTable(table) {
+		return table, nil
+	}
+
+	return "", fmt.Errorf("table %s or %s not found", name, table)
+}
+
+// parseYAMLFixture parses a YAML or JSON fixture, a map of rows by label or a list of rows.
+func parseYAMLFixture(content []byte) ([]fixtureRow, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	var rows []fixtureRow
+	switch root := doc.Content[0]; root.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			values, err := fixtureValues(root.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, fixtureRow{label: root.Content[i].Value, values: values})
+		}
+	case yaml.SequenceNode:
+		for _, node := range root.Content {
+			values, err := fixtureValues(node)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, fixtureRow{values: values})
+		}
+	default:
+		return nil, fmt.Errorf("line %d: expected a map or a list of rows", root.Line)
+	}
+
+	return rows, nil
+}
+
+// fixtureValues returns the column values of a row node, maps and lists are encoded as JSON.
+func fixtureValues(node *yaml.Node) (map[string]any, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a row of columns", node.Line)
+	}
+
+	values := make(map[string]any, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value any
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return nil, err
+		}
+
+		switch value.(type) {
+		case map[string]any, []any:
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			value = string(encoded)
+		}
+
+		values[node.Content[i].Value] = value
+	}
+
+	return values, nil
+}
+
+// parseCSVFixture parses a CSV fixture with a header row.
+func parseCSVFixture(content []byte) ([]fixtureRow, error) {
+	records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	rows := make([]fixtureRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := fixtureRow{values: make(map[string]any, len(header))}
+		for i, column := range header {
+			switch {
+			case column == fixtureLabelKey:
+				row.label = record[i]
+			case record[i] == fixtureCSVNull:
+				row.values[column] = nil
+			default:
+				row.values[column] = record[i]
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
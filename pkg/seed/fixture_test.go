@@ -0,0 +1,187 @@
+// ABOUTME: Tests for loading YAML, JSON and CSV fixture files into the database.
+// ABOUTME: Verifies references between fixtures, upsert, truncate, rollback and the load order.
+package seed
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type fixtureRole struct {
+	ID   uint
+	Name string
+}
+
+type fixtureUser struct {
+	ID       uint
+	Name     string
+	Email    string
+	RoleID   *uint
+	Settings string
+}
+
+type fixturePost struct {
+	ID       uint
+	Title    string
+	AuthorID uint
+}
+
+// setupFixtures writes files to a fixture directory and returns it with a database having the
+// roles, users and posts tables.
+func setupFixtures(t *testing.T, files map[string]string) (*gorm.DB, string) {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write fixture: %v", err)
+		}
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	if err := db.Table("roles").AutoMigrate(&fixtureRole{}); err != nil {
+		t.Fatalf("Failed to migrate roles: %v", err)
+	}
+	if err := db.Table("users").AutoMigrate(&fixtureUser{}); err != nil {
+		t.Fatalf("Failed to migrate users: %v", err)
+	}
+	if err := db.Table("posts").AutoMigrate(&fixturePost{}); err != nil {
+		t.Fatalf("Failed to migrate posts: %v", err)
+	}
+
+	return db, dir
+}
+
+func TestLoadFixtures(t *testing.T) {
+	db, dir := setupFixtures(t, map[string]string{
+		// Named after the model, refers to users loaded first.
+		"Post.json": `{"hello": {"title": "Hello", "author_id": "@users.alice"}}`,
+		"users.yaml": `
+alice:
+  name: Alice
+  email: "@@alice"
+  role_id: "@roles.admin"
+  settings:
+    theme: dark
+bob:
+  name: Bob
+  role_id: null
+`,
+		"roles.csv": "_name,id,name\nadmin,7,Admin\n,8,Guest\n",
+	})
+
+	if err := LoadFixtures(db, dir, FixtureOptions{}); err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+
+	var users []fixtureUser
+	db.Table("users").Order("id").Find(&users)
+	if len(users) != 2 || users[0].RoleID == nil || *users[0].RoleID != 7 || users[1].RoleID != nil {
+		t.Fatalf("Unexpected users: %+v", users)
+	}
+	if users[0].Email != "@alice" || users[0].Settings != `{"theme":"dark"}` {
+		t.Errorf("Unexpected values of alice: %+v", users[0])
+	}
+
+	var post fixturePost
+	db.Table("posts").First(&post)
+	if post.Title != "Hello" || post.AuthorID != users[0].ID {
+		t.Errorf("Expected post by alice (%d), got %+v", users[0].ID, post)
+	}
+
+	var roles int64
+	db.Table("roles").Count(&roles)
+	if roles != 2 {
+		t.Errorf("Expected 2 roles, got %d", roles)
+	}
+}
+
+func TestLoadFixtures_UpsertAndTruncate(t *testing.T) {
+	db, dir := setupFixtures(t, map[string]string{
+		"roles.yaml": "- {id: 1, name: Admin}\n- {id: 2, name: Editor}\n",
+	})
+	db.Table("roles").Create(map[string]any{"id": 1, "name": "Old"})
+	db.Table("roles").Create(map[string]any{"id": 3, "name": "Stale"})
+
+	if err := LoadFixtures(db, dir, FixtureOptions{}); err == nil {
+		t.Fatal("Expected duplicate primary key error without upsert")
+	}
+
+	if err := LoadFixtures(db, dir, FixtureOptions{Upsert: true}); err != nil {
+		t.Fatalf("LoadFixtures with upsert failed: %v", err)
+	}
+	var roles []fixtureRole
+	db.Table("roles").Order("id").Find(&roles)
+	if len(roles) != 3 || roles[0].Name != "Admin" || roles[2].Name != "Stale" {
+		t.Errorf("Unexpected roles after upsert: %+v", roles)
+	}
+
+	if err := LoadFixtures(db, dir, FixtureOptions{Truncate: true}); err != nil {
+		t.Fatalf("LoadFixtures with truncate failed: %v", err)
+	}
+	roles = nil
+	db.Table("roles").Order("id").Find(&roles)
+	if len(roles) != 2 || roles[1].Name != "Editor" {
+		t.Errorf("Unexpected roles after truncate: %+v", roles)
+	}
+}
+
+func TestLoadFixtures_Errors(t *testing.T) {
+	db, dir := setupFixtures(t, map[string]string{
+		"users.yaml": "alice: {name: Alice, role_id: '@roles.admin'}\n",
+		"roles.yaml": "admin: {name: Admin, id: '@users.alice'}\n",
+	})
+	if err := LoadFixtures(db, dir, FixtureOptions{}); !errors.Is(err, ErrFixtureCycle) {
+		t.Errorf("LoadFixtures error = %v, want %v", err, ErrFixtureCycle)
+	}
+
+	db, dir = setupFixtures(t, map[string]string{"comments.yaml": "- {body: Hi}\n"})
+	if err := LoadFixtures(db, dir, FixtureOptions{}); err == nil {
+		t.Error("Expected error for a fixture without table")
+	}
+
+	// A failed row rolls back the rows of its file.
+	db, dir = setupFixtures(t, map[string]string{"roles.yaml": "- {id: 1, name: Admin}\n- {id: 1, name: Again}\n"})
+	if err := LoadFixtures(db, dir, FixtureOptions{}); err == nil {
+		t.Fatal("Expected duplicate primary key error")
+	}
+	var count int64
+	db.Table("roles").Count(&count)
+	if count != 0 {
+		t.Errorf("Expected the roles file to be rolled back, got %d rows", count)
+	}
+
+	// A failed file rolls back the truncation and the files loaded before it.
+	db, dir = setupFixtures(t, map[string]string{
+		"roles.yaml": "admin: {id: 1, name: Admin}\n",
+		"users.yaml": "- {name: Alice, role_id: '@roles.admin'}\n- {id: 5, name: Bob}\n- {id: 5, name: Again}\n",
+	})
+	db.Table("users").Create(map[string]any{"id": 9, "name": "Kept"})
+	if err := LoadFixtures(db, dir, FixtureOptions{Truncate: true}); err == nil {
+		t.Fatal("Expected duplicate primary key error")
+	}
+	var users []fixtureUser
+	db.Table("users").Find(&users)
+	db.Table("roles").Count(&count)
+	if len(users) != 1 || users[0].Name != "Kept" || count != 0 {
+		t.Errorf("Expected the load rolled back, got users %+v and %d roles", users, count)
+	}
+
+	// Without truncate, the files loaded before the failed one are kept.
+	if err := LoadFixtures(db, dir, FixtureOptions{}); err == nil {
+		t.Fatal("Expected duplicate primary key error")
+	}
+	users = nil
+	db.Table("users").Find(&users)
+	db.Table("roles").Count(&count)
+	if len(users) != 1 || count != 1 {
+		t.Errorf("Expected only the roles file loaded, got users %+v and %d roles", users, count)
+	}
+}
@@ -4,8 +4,14 @@ package runner
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -28,7 +34,9 @@ type Runner struct {
 	seederDir   string
 	seederPath  string
 
-	dbOptions *db.MySQLOptions
+	dbOptions *db.Options
+	history   bool
+	table     string
 
 	cacheDir string
 	tmpDir   string
@@ -73,7 +81,9 @@ func NewRunner(verbose, rebuild bool) (*Runner, error) {
 		userModule:  userModule,
 		seederDir:   seederDir,
 		seederPath:  seederPath,
-		dbOptions:   config.Cfg.MysqlOptions,
+		dbOptions:   config.Cfg.GetDatabaseOptions(),
+		history:     config.Cfg.Seed.History,
+		table:       config.Cfg.GetSeedTable(),
 		cacheDir:    cacheDir,
 		tmpDir:      tmpDir,
 		verbose:     verbose,
@@ -81,8 +91,56 @@ func NewRunner(verbose, rebuild bool) (*Runner, error) {
 	}, nil
 }
 
-// Run executes the seed command.
-func (r *Runner) Run(seederName string) error {
+// Run runs the seeder named seederName, or all seeders if empty. With seed.history, registered seeders
+// which already ran are skipped unless force is set.
+func (r *Runner) Run(seederName string, force bool) error {
+	if force && !r.history {
+		return errors.New("--force reruns recorded seeders, seeders run every time unless seed.history is enabled")
+	}
+
+	args := []string{"--seeder", seederName}
+	if r.history {
+		args = append(args, "--history")
+	}
+	if force {
+		args = append(args, "--force")
+	}
+
+	return r.run(args...)
+}
+
+// Status prints the registered seeders with the time they ran in output format, table or json.
+func (r *Runner) Status(output string) error {
+	args := []string{"--status", "--output", output}
+	if r.history {
+		args = append(args, "--history")
+	}
+
+	return r.run(args...)
+}
+
+// Fixtures loads the fixture files in dir instead of running seeders.
+func (r *Runner) Fixtures(dir string, upsert, truncate bool) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("fixture directory not found: %s", dir)
+	}
+
+	args := []string{"--fixtures", dir}
+	if upsert {
+		args = append(args, "--upsert")
+	}
+	if truncate {
+		args = append(args, "--truncate")
+	}
+
+	return r.run(args...)
+}
+
+func (r *Runner) run(args ...string) error {
 	if err := r.validate(); err != nil {
 		return err
 	}
@@ -98,7 +156,7 @@ func (r *Runner) Run(seederName string) error {
 		}
 	}
 
-	return r.execute(seederName)
+	return r.execute(args...)
 }
 
 func (r *Runner) validate() error {
@@ -110,6 +168,10 @@ func (r *Runner) validate() error {
 		return fmt.Errorf("database configuration not found in .bingo.yaml")
 	}
 
+	if err := r.dbOptions.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -126,7 +188,7 @@ func (r *Runner) needsBuild() (bool, error) {
 		return true, nil
 	}
 
-	newChecksum, err := CalculateChecksum(r.seederPath)
+	newChecksum, err := r.checksum()
 	if err != nil {
 		return true, nil
 	}
@@ -155,7 +217,7 @@ func (r *Runner) build() error {
 		return fmt.Errorf("build failed (use --verbose for details): %w", err)
 	}
 
-	checksum, err := CalculateChecksum(r.seederPath)
+	checksum, err := r.checksum()
 	if err != nil {
 		return fmt.Errorf("failed to calculate checksum: %w", err)
 	}
@@ -179,8 +241,15 @@ func (r *Runner) generateMainGo() error {
 		return err
 	}
 
-	data := map[string]string{
-		"SeederImport": r.userModule + "/" + r.seederDir,
+	funcs, err := declaredFuncs(r.seederPath)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]any{
+		"SeederImport":  r.userModule + "/" + r.seederDir,
+		"HasInit":       funcs["Init"],
+		"HasRunSeeders": funcs["RunSeeders"],
 	}
 
 	var buf bytes.Buffer
@@ -213,19 +282,19 @@ func (r *Runner) runBuildCommand(outputPath string) error {
 	return nil
 }
 
-func (r *Runner) execute(seederName string) error {
+func (r *Runner) execute(extra ...string) error {
 	binaryPath := r.binaryPath()
 
 	args := []string{
+		"--driver", r.dbOptions.GetDriver(),
 		"--host", r.dbOptions.Host,
 		"--username", r.dbOptions.Username,
 		"--password", r.dbOptions.Password,
 		"--database", r.dbOptions.Database,
+		"--sslmode", r.dbOptions.SSLMode,
+		"--table", r.table,
 	}
-
-	if seederName != "" {
-		args = append(args, "--seeder", seederName)
-	}
+	args = append(args, extra...)
 
 	cmd := exec.Command(binaryPath, args...)
 	cmd.Stdout = os.Stdout
@@ -234,6 +303,49 @@ func (r *Runner) execute(seederName string) error {
 	return cmd.Run()
 }
 
+// checksum returns the checksum of the seeder files and the main.go template.
+func (r *Runner) checksum() (string, error) {
+	checksum, err := CalculateChecksum(r.seederPath)
+	if err != nil {
+		return "", err
+	}
+
+	tplContent, err := tplFS.ReadFile("tpl/main.go.tpl")
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(checksum))
+	h.Write(tplContent)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// declaredFuncs returns the top-level functions declared in the package in dir, e.g. Init and
+// RunSeeders of projects seeding without seed.Register.
+func declaredFuncs(dir string) (map[string]bool, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+					funcs[fn.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	return funcs, nil
+}
+
 func (r *Runner) binaryPath() string {
 	name := "seeder"
 	if runtime.GOOS == "windows" {
@@ -2,49 +2,128 @@
 package main
 
 import (
+{{- if .HasRunSeeders}}
+	"errors"
+{{- end}}
 	"fmt"
 	"os"
 
+{{- if or .HasInit .HasRunSeeders}}
+
 	"{{.SeederImport}}"
+{{- else}}
+
+	_ "{{.SeederImport}}"
+{{- end}}
 
 	"github.com/bingo-project/bingoctl/pkg/db"
+	"github.com/bingo-project/bingoctl/pkg/seed"
 	"github.com/spf13/pflag"
+	"gorm.io/gorm"
 )
 
 func main() {
 	var (
-		host     string
-		username string
-		password string
-		database string
+		driver     string
+		host       string
+		username   string
+		password   string
+		database   string
+		sslMode    string
+		table      string
 		seederName string
+		history    bool
+		force      bool
+		status     bool
+		output     string
+		fixtures   string
+		upsert     bool
+		truncate   bool
 	)
 
+	pflag.StringVar(&driver, "driver", "mysql", "database driver: mysql, postgres, sqlite")
 	pflag.StringVar(&host, "host", "", "database host")
 	pflag.StringVar(&username, "username", "", "database username")
 	pflag.StringVar(&password, "password", "", "database password")
-	pflag.StringVar(&database, "database", "", "database name")
+	pflag.StringVar(&database, "database", "", "database name, or file path for sqlite")
+	pflag.StringVar(&sslMode, "sslmode", "", "postgres ssl mode")
+	pflag.StringVar(&table, "table", "", "seeder history table, default bingo_seeder")
 	pflag.StringVar(&seederName, "seeder", "", "specific seeder to run")
+	pflag.BoolVar(&history, "history", false, "record the seeders which ran in the history table and skip them")
+	pflag.BoolVar(&force, "force", false, "rerun seeders which already ran")
+	pflag.BoolVar(&status, "status", false, "list the seeders and when they ran")
+	pflag.StringVar(&output, "output", "table", "status output format: table, json")
+	pflag.StringVar(&fixtures, "fixtures", "", "load the fixture files in this directory instead of running seeders")
+	pflag.BoolVar(&upsert, "upsert", false, "update fixture rows whose primary key exists")
+	pflag.BoolVar(&truncate, "truncate", false, "delete the rows of the fixture tables before loading")
 	pflag.Parse()
 
 	// Connect to database
-	dbConn, err := db.NewMySQL(&db.MySQLOptions{
+	dbConn, err := db.NewDB(&db.Options{
+		Driver:   driver,
 		Host:     host,
 		Username: username,
 		Password: password,
 		Database: database,
+		SSLMode:  sslMode,
 	})
 	if err != nil {
-		fmt.Printf("Failed to connect database: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to connect database: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Initialize seeder with database connection
+	seed.SetDB(dbConn)
+{{- if .HasInit}}
 	seeder.Init(dbConn)
+{{- end}}
 
-	// Run seeders
-	if err := seeder.RunSeeders(seederName); err != nil {
-		fmt.Printf("Seeder failed: %v\n", err)
+	if fixtures != "" {
+		err = seed.LoadFixtures(dbConn, fixtures, seed.FixtureOptions{Upsert: upsert, Truncate: truncate})
+	} else {
+		err = run(dbConn, table, seederName, history, force, status, output)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Seeder failed: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// run runs the seeders registered with seed.Register, once if history is set.
+{{- if .HasRunSeeders}}
+// Projects registering no seeders run them all by RunSeeders every time.
+{{- end}}
+func run(dbConn *gorm.DB, table, seederName string, history, force, status bool, output string) error {
+{{- if .HasRunSeeders}}
+	if len(seed.Registered()) == 0 {
+		if status {
+			fmt.Println("No seeders registered with seed.Register, seeders run by RunSeeders aren't recorded.")
+
+			return nil
+		}
+
+		return seeder.RunSeeders(seederName)
+	}
+
+	// RunSeeders would run the registered seeders again, and skipping it would skip the others.
+	if !status {
+		return errors.New("seeders are registered with seed.Register but the seeder package also declares RunSeeders: " +
+			"register the seeders RunSeeders runs in init() and remove RunSeeders")
+	}
+{{- end}}
+
+	runner := seed.NewRunner(dbConn, table)
+	runner.History = history
+
+	if status {
+		if !history {
+			fmt.Println("Seeder history is disabled, enable seed.history in .bingo.yaml to record the seeders which ran.")
+		}
+
+		return runner.PrintStatus(os.Stdout, output)
+	}
+
+	runner.Force = force
+
+	return runner.Run(seederName)
+}
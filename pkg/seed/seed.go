@@ -0,0 +1,271 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bingo-project/component-base/cli/console"
+	"github.com/mgutz/ansi"
+	"gorm.io/gorm"
+)
+
+// DefaultTableName is the default table recording the seeders that ran.
+const DefaultTableName = "bingo_seeder"
+
+var (
+	// ErrSeederNotFound is returned by Run when no registered seeder matches the name or a dependency.
+	ErrSeederNotFound = errors.New("seeder not found")
+
+	// ErrDependencyCycle is returned by Run when seeders depend on each other.
+	ErrDependencyCycle = errors.New("seeder dependency cycle")
+)
+
+// Seeder seeds the database, seeders generated by make seeder implement it.
+type Seeder interface {
+	// Signature is the name of the seeder recorded in the history table.
+	Signature() string
+
+	// Run seeds the database.
+	Run() error
+}
+
+// DependentSeeder is a seeder which needs other seeders to run first, e.g. users need roles.
+type DependentSeeder interface {
+	Seeder
+
+	// Dependencies are the names of the seeders to run first, matched like the --seeder flag.
+	Dependencies() []string
+}
+
+var (
+	seeders []Seeder
+	seedDB  *gorm.DB
+)
+
+// Register registers seeders to run by db seed, usually from init() of the seeder files.
+func Register(s ...Seeder) {
+	seeders = append(seeders, s...)
+}
+
+// Registered returns the registered seeders in registration order.
+func Registered() []Seeder {
+	return seeders
+}
+
+// SetDB sets the connection returned by DB, the seed runner sets it before running seeders.
+func SetDB(db *gorm.DB) {
+	seedDB = db
+}
+
+// DB returns the connection of the database being seeded.
+func DB() *gorm.DB {
+	return seedDB
+}
+
+// History is a seeder recorded in the history table.
+type History struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement;"`
+	Seeder string `gorm:"type:varchar(255);not null;unique;"`
+	RanAt  time.Time
+}
+
+// Runner runs the registered seeders. With History set, the seeders that ran are recorded in the history
+// table and run once.
+type Runner struct {
+	DB    *gorm.DB
+	Table string
+
+	// History records the seeders that ran in Table and skips them in later runs.
+	History bool
+
+	// Force reruns seeders which already ran.
+	Force bool
+}
+
+// NewRunner returns a runner recording seeders in table, DefaultTableName if empty. The table is created
+// when the first seeder is recorded.
+func NewRunner(db *gorm.DB, table string) *Runner {
+	if table == "" {
+		table = DefaultTableName
+	}
+
+	return &Runner{DB: db, Table: table}
+}
+
+// Run runs the seeder matching name after its dependencies, or all registered seeders ordered by
+// their dependencies if name is empty. With History, seeders which already ran are skipped unless Force
+// is set, which doesn't rerun the dependencies of the named seeder. It stops at the first seeder that fails.
+func (r *Runner) Run(name string) error {
+	targets, err := plan(name)
+	if err != nil {
+		return err
+	}
+
+	var records []History
+	if r.History {
+		records, err = r.records()
+		if err != nil {
+			return err
+		}
+	}
+	history := make(map[string]bool, len(records))
+	for _, record := range records {
+		history[record.Seeder] = true
+	}
+
+	ran := 0
+	for i, seeder := range targets {
+		// The named seeder is the last one, after its dependencies.
+		named := name != "" && i == len(targets)-1
+
+		signature := seeder.Signature()
+		if history[signature] && !(r.Force && (name == "" || named)) {
+			if named {
+				console.Info(fmt.Sprintf("Seeder %s already ran, use --force to run it again.", signature))
+			}
+
+			continue
+		}
+
+		fmt.Printf("%s %s\n", ansi.Color("Seeding:", "yellow"), signature)
+		if err := seeder.Run(); err != nil {
+			fmt.Printf("%s  %s\n", ansi.Color("Failed:", "red"), signature)
+
+			return err
+		}
+
+		if r.History {
+			if err := r.record(signature); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%s   %s\n", ansi.Color("Seeded:", "green"), signature)
+		ran++
+	}
+
+	if ran == 0 && name == "" {
+		console.Info("Nothing to seed.")
+	}
+
+	return nil
+}
+
+// records returns the seeders recorded in the history table in the order they first ran, none if the
+// table doesn't exist.
+func (r *Runner) records() ([]History, error) {
+	if !r.DB.Migrator().HasTable(r.Table) {
+		return nil, nil
+	}
+
+	var records []History
+	err := r.DB.Table(r.Table).Order("id").Find(&records).Error
+
+	return records, err
+}
+
+// record records signature ran now, updating the time if it ran before. It creates the history table
+// if it doesn't exist.
+func (r *Runner) record(signature string) error {
+	if !r.DB.Migrator().HasTable(r.Table) {
+		if err := r.DB.Table(r.Table).Migrator().CreateTable(&History{}); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	result := r.DB.Table(r.Table).Where("seeder = ?", signature).Update("ran_at", now)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	return r.DB.Table(r.Table).Create(&History{Seeder: signature, RanAt: now}).Error
+}
+
+// plan returns the seeders to run for name in order: the seeder matching name after its
+// dependencies, or all registered seeders if name is empty.
+func plan(name string) ([]Seeder, error) {
+	if name == "" {
+		return sortSeeders(seeders)
+	}
+
+	seeder := find(name)
+	if seeder == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSeederNotFound, name)
+	}
+
+	return sortSeeders([]Seeder{seeder})
+}
+
+// sortSeeders returns targets and their dependencies sorted so that every seeder comes after its
+// dependencies. Seeders without dependencies between them keep the order of targets.
+func sortSeeders(targets []Seeder) ([]Seeder, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	var (
+		sorted []Seeder
+		path   []string
+		state  = make(map[string]int)
+	)
+
+	var visit func(seeder Seeder) error
+	visit = func(seeder Seeder) error {
+		signature := seeder.Signature()
+		switch state[signature] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(slices.Clone(path[slices.Index(path, signature):]), signature)
+
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+
+		state[signature] = visiting
+		path = append(path, signature)
+
+		if dependent, ok := seeder.(DependentSeeder); ok {
+			for _, name := range dependent.Dependencies() {
+				dependency := find(name)
+				if dependency == nil {
+					return fmt.Errorf("%w: %s, a dependency of %s", ErrSeederNotFound, name, signature)
+				}
+
+				if err := visit(dependency); err != nil {
+					return err
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[signature] = visited
+		sorted = append(sorted, seeder)
+
+		return nil
+	}
+
+	for _, seeder := range targets {
+		if err := visit(seeder); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// find returns the registered seeder whose signature is name or name followed by Seeder,
+// case-insensitive, e.g. User matches UserSeeder.
+func find(name string) Seeder {
+	for _, seeder := range seeders {
+		signature := seeder.Signature()
+		if strings.EqualFold(signature, name) || strings.EqualFold(signature, name+"Seeder") {
+			return seeder
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+// ABOUTME: Tests for running registered seeders with the seeder history table.
+// ABOUTME: Verifies seeders run once after their dependencies with history, --force reruns them and the status lists them.
+package seed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type countSeeder struct {
+	name string
+	runs *int
+}
+
+func (s countSeeder) Signature() string { return s.name }
+
+func (s countSeeder) Run() error {
+	*s.runs++
+
+	return nil
+}
+
+// orderSeeder appends its name to order when it runs.
+type orderSeeder struct {
+	name  string
+	deps  []string
+	order *[]string
+}
+
+func (s orderSeeder) Signature() string { return s.name }
+
+func (s orderSeeder) Dependencies() []string { return s.deps }
+
+func (s orderSeeder) Run() error {
+	*s.order = append(*s.order, s.name)
+
+	return nil
+}
+
+// registerOrdered registers seeders named names in order with their dependencies in deps, and returns
+// the names of the seeders in the order they run.
+func registerOrdered(names []string, deps map[string][]string) *[]string {
+	order := new([]string)
+	for _, name := range names {
+		Register(orderSeeder{name: name, deps: deps[name], order: order})
+	}
+
+	return order
+}
+
+// setupRunner registers seeders named names and returns a runner on an empty database with their run counts.
+func setupRunner(t *testing.T, names ...string) (*Runner, map[string]*int) {
+	original := seeders
+	seeders = nil
+	t.Cleanup(func() { seeders = original })
+
+	runs := make(map[string]*int)
+	for _, name := range names {
+		runs[name] = new(int)
+		Register(countSeeder{name: name, runs: runs[name]})
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+
+	r := NewRunner(db, "")
+	r.History = true
+
+	return r, runs
+}
+
+func TestRunner_RunOnce(t *testing.T) {
+	r, runs := setupRunner(t, "RoleSeeder", "UserSeeder")
+
+	for i := 0; i < 2; i++ {
+		if err := r.Run(""); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+	if *runs["RoleSeeder"] != 1 || *runs["UserSeeder"] != 1 {
+		t.Errorf("Expected each seeder to run once, got %d and %d", *runs["RoleSeeder"], *runs["UserSeeder"])
+	}
+
+	r.Force = true
+	if err := r.Run("user"); err != nil {
+		t.Fatalf("Run with force failed: %v", err)
+	}
+	if *runs["RoleSeeder"] != 1 || *runs["UserSeeder"] != 2 {
+		t.Errorf("Expected only UserSeeder to rerun, got %d and %d", *runs["RoleSeeder"], *runs["UserSeeder"])
+	}
+
+	var count int64
+	r.DB.Table(DefaultTableName).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected 2 history records, got %d", count)
+	}
+}
+
+func TestRunner_RunWithoutHistory(t *testing.T) {
+	r, runs := setupRunner(t, "RoleSeeder")
+	r.History = false
+
+	for i := 0; i < 2; i++ {
+		if err := r.Run(""); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+	if *runs["RoleSeeder"] != 2 {
+		t.Errorf("Expected the seeder to run every time, got %d runs", *runs["RoleSeeder"])
+	}
+
+	if _, err := r.Status(); err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if r.DB.Migrator().HasTable(DefaultTableName) {
+		t.Error("Expected no history table without History")
+	}
+}
+
+func TestRunner_RunNotFound(t *testing.T) {
+	r, _ := setupRunner(t, "UserSeeder")
+
+	if err := r.Run("Post"); !errors.Is(err, ErrSeederNotFound) {
+		t.Fatalf("Run error = %v, want %v", err, ErrSeederNotFound)
+	}
+}
+
+func TestRunner_RunInDependencyOrder(t *testing.T) {
+	r, _ := setupRunner(t)
+	order := registerOrdered([]string{"PostSeeder", "UserSeeder", "TagSeeder", "RoleSeeder"}, map[string][]string{
+		"PostSeeder": {"User", "TagSeeder"},
+		"UserSeeder": {"Role"},
+	})
+
+	if err := r.Run(""); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := "RoleSeeder,UserSeeder,TagSeeder,PostSeeder"
+	if got := strings.Join(*order, ","); got != want {
+		t.Errorf("Run order = %s, want %s", got, want)
+	}
+}
+
+func TestRunner_RunNamedWithDependencies(t *testing.T) {
+	r, _ := setupRunner(t)
+	order := registerOrdered([]string{"PostSeeder", "UserSeeder", "RoleSeeder", "TagSeeder"}, map[string][]string{
+		"PostSeeder": {"UserSeeder"},
+		"UserSeeder": {"RoleSeeder"},
+	})
+
+	if err := r.Run("Post"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got, want := strings.Join(*order, ","), "RoleSeeder,UserSeeder,PostSeeder"; got != want {
+		t.Errorf("Run order = %s, want %s", got, want)
+	}
+
+	// Force reruns the named seeder, not its dependencies which already ran.
+	*order = nil
+	r.Force = true
+	if err := r.Run("Post"); err != nil {
+		t.Fatalf("Run with force failed: %v", err)
+	}
+	if got, want := strings.Join(*order, ","), "PostSeeder"; got != want {
+		t.Errorf("Run order with force = %s, want %s", got, want)
+	}
+}
+
+func TestRunner_RunDependencyErrors(t *testing.T) {
+	r, _ := setupRunner(t)
+	order := registerOrdered([]string{"RoleSeeder", "UserSeeder", "PostSeeder", "TagSeeder"}, map[string][]string{
+		"UserSeeder": {"PostSeeder"},
+		"PostSeeder": {"UserSeeder"},
+		"TagSeeder":  {"MissingSeeder"},
+	})
+
+	err := r.Run("")
+	if !errors.Is(err, ErrDependencyCycle) || !strings.Contains(err.Error(), "UserSeeder -> PostSeeder -> UserSeeder") {
+		t.Fatalf("Run error = %v, want %v with the cycle", err, ErrDependencyCycle)
+	}
+	if len(*order) != 0 {
+		t.Errorf("Expected no seeder to run with a cycle, ran %v", *order)
+	}
+
+	if err := r.Run("Tag"); !errors.Is(err, ErrSeederNotFound) {
+		t.Errorf("Run error = %v, want %v", err, ErrSeederNotFound)
+	}
+}
+
+func TestRunner_Status(t *testing.T) {
+	r, _ := setupRunner(t, "RoleSeeder", "UserSeeder")
+
+	if err := r.Run("Role"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if err := r.record("RemovedSeeder"); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	statuses, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+
+	if len(statuses) != 3 {
+		t.Fatalf("Expected 3 statuses, got %+v", statuses)
+	}
+	if s := statuses[0]; s.Seeder != "RoleSeeder" || !s.Ran || s.RanAt == nil {
+		t.Errorf("Expected RoleSeeder ran, got %+v", s)
+	}
+	if s := statuses[1]; s.Seeder != "UserSeeder" || s.Ran {
+		t.Errorf("Expected UserSeeder pending, got %+v", s)
+	}
+	if s := statuses[2]; s.Seeder != "RemovedSeeder" || !s.Orphaned {
+		t.Errorf("Expected RemovedSeeder orphaned, got %+v", s)
+	}
+}
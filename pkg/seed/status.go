@@ -0,0 +1,101 @@
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mgutz/ansi"
+)
+
+// Output formats of PrintStatus.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// SeederStatus is the state of a seeder.
+type SeederStatus struct {
+	Seeder string     `json:"seeder"`
+	Ran    bool       `json:"ran"`
+	RanAt  *time.Time `json:"ranAt,omitempty"`
+
+	// Orphaned is set for seeders recorded in the history table which aren't registered.
+	Orphaned bool `json:"orphaned,omitempty"`
+}
+
+// Status returns the registered seeders marked ran or pending, followed by the orphaned ones.
+func (r *Runner) Status() ([]SeederStatus, error) {
+	records, err := r.records()
+	if err != nil {
+		return nil, err
+	}
+	history := make(map[string]History, len(records))
+	for _, record := range records {
+		history[record.Seeder] = record
+	}
+
+	registered := make(map[string]bool, len(seeders))
+	statuses := make([]SeederStatus, 0, len(seeders))
+	for _, seeder := range seeders {
+		signature := seeder.Signature()
+		registered[signature] = true
+
+		status := SeederStatus{Seeder: signature}
+		if record, ok := history[signature]; ok {
+			status.Ran, status.RanAt = true, &record.RanAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range records {
+		if !registered[record.Seeder] {
+			statuses = append(statuses, SeederStatus{Seeder: record.Seeder, Ran: true, RanAt: &record.RanAt, Orphaned: true})
+		}
+	}
+
+	return statuses, nil
+}
+
+// PrintStatus writes the seeder status to w in output format, table or json.
+func (r *Runner) PrintStatus(w io.Writer, output string) error {
+	statuses, err := r.Status()
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(statuses)
+	case OutputTable, "":
+	default:
+		return fmt.Errorf("unsupported output format %q, use %s or %s", output, OutputTable, OutputJSON)
+	}
+
+	if len(statuses) == 0 {
+		_, err := fmt.Fprintln(w, "No seeders found.")
+
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEEDER\tRAN AT\tSTATUS")
+	for _, status := range statuses {
+		ranAt, state := "", ansi.Color("Pending", "yellow")
+		if status.Ran {
+			ranAt, state = status.RanAt.Local().Format(time.DateTime), ansi.Color("Ran", "green")
+		}
+		if status.Orphaned {
+			state = ansi.Color("Orphaned (not registered)", "red")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", status.Seeder, ranAt, state)
+	}
+
+	return tw.Flush()
+}
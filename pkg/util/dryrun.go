@@ -0,0 +1,190 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mgutz/ansi"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DryRun records file writes instead of touching disk.
+var DryRun bool
+
+// FileChange is a file write or removal recorded in dry-run mode.
+type FileChange struct {
+	Path    string
+	Old     []byte
+	New     []byte
+	Exists  bool
+	Deleted bool
+}
+
+var changes []*FileChange
+
+// WriteFile writes content to path, creating parent directories as needed.
+// In dry-run mode the change is recorded and nothing is written.
+func WriteFile(path string, content []byte) error {
+	if !DryRun {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		return os.WriteFile(path, content, 0644)
+	}
+
+	change, err := recordChange(path)
+	if err != nil {
+		return err
+	}
+
+	change.New = content
+	change.Deleted = false
+
+	return nil
+}
+
+// RemoveFile removes path, it's not an error if path does not exist.
+// In dry-run mode the removal is recorded and nothing is removed.
+func RemoveFile(path string) error {
+	if !DryRun {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	change, err := recordChange(path)
+	if err != nil {
+		return err
+	}
+
+	change.New = nil
+	change.Deleted = true
+
+	return nil
+}
+
+// recordChange returns the recorded change of path, recording the current content if it's the first one.
+func recordChange(path string) (*FileChange, error) {
+	for _, change := range changes {
+		if change.Path == path {
+			return change, nil
+		}
+	}
+
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	change := &FileChange{Path: path, Old: old, Exists: err == nil}
+	changes = append(changes, change)
+
+	return change, nil
+}
+
+// ReadFile reads path, including changes pending in dry-run mode.
+func ReadFile(path string) ([]byte, error) {
+	for _, change := range changes {
+		if change.Path == path {
+			if change.Deleted {
+				return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+			}
+
+			return change.New, nil
+		}
+	}
+
+	return os.ReadFile(path)
+}
+
+// Changes returns the file writes recorded in dry-run mode.
+func Changes() []*FileChange {
+	return changes
+}
+
+// PrintChanges prints the recorded file list followed by unified diffs.
+func PrintChanges(w io.Writer) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "Dry run: nothing would be written.")
+
+		return err
+	}
+
+	fmt.Fprintln(w, "Dry run: no files were written.")
+	fmt.Fprintln(w)
+	colors := map[string]string{"create": "green", "update": "yellow", "delete": "red"}
+	for _, change := range changes {
+		action := change.Action()
+		if color, ok := colors[action]; ok {
+			action = ansi.Color(action, color)
+		}
+
+		fmt.Fprintf(w, "  %s %s\n", action, change.Path)
+	}
+
+	for _, change := range changes {
+		if change.Action() == "unchanged" {
+			continue
+		}
+
+		diff, err := change.Diff()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w)
+		fmt.Fprint(w, diff)
+	}
+
+	return nil
+}
+
+// Action describes the change: create, update, delete or unchanged.
+func (c *FileChange) Action() string {
+	switch {
+	case c.Deleted && !c.Exists:
+		return "unchanged"
+	case c.Deleted:
+		return "delete"
+	case !c.Exists:
+		return "create"
+	case bytes.Equal(c.Old, c.New):
+		return "unchanged"
+	default:
+		return "update"
+	}
+}
+
+// Diff returns the unified diff of the change.
+func (c *FileChange) Diff() (string, error) {
+	from, to := "a/"+c.Path, "b/"+c.Path
+	if !c.Exists {
+		from = "/dev/null"
+	}
+	if c.Deleted {
+		to = "/dev/null"
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(c.Old),
+		B:        splitLines(c.New),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	})
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	return difflib.SplitLines(string(content))
+}
@@ -0,0 +1,122 @@
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupDryRun(t *testing.T) {
+	DryRun = true
+	changes = nil
+	t.Cleanup(func() {
+		DryRun = false
+		changes = nil
+	})
+}
+
+func TestWriteFile_DryRun(t *testing.T) {
+	setupDryRun(t)
+
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "registry.go")
+	if err := os.WriteFile(existing, []byte("package store\n\ntype IStore interface {\n}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	created := filepath.Join(tmpDir, "pkg", "user.go")
+	if err := WriteFile(created, []byte("package pkg\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := WriteFile(existing, []byte("package store\n\ntype IStore interface {\n\tUser() UserStore\n}\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	// Nothing touches disk
+	if Exists(filepath.Join(tmpDir, "pkg")) {
+		t.Error("Directory should not be created in dry-run mode")
+	}
+	content, _ := os.ReadFile(existing)
+	if strings.Contains(string(content), "User()") {
+		t.Error("Existing file should not be modified in dry-run mode")
+	}
+
+	// Pending content is visible to later reads
+	pending, err := ReadFile(existing)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(pending), "User()") {
+		t.Error("ReadFile should return pending content in dry-run mode")
+	}
+
+	if len(Changes()) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(Changes()))
+	}
+	if Changes()[0].Action() != "create" || Changes()[1].Action() != "update" {
+		t.Errorf("Unexpected actions: %s, %s", Changes()[0].Action(), Changes()[1].Action())
+	}
+
+	var buf bytes.Buffer
+	if err := PrintChanges(&buf); err != nil {
+		t.Fatalf("PrintChanges failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, expected := range []string{"--- /dev/null", "+package pkg", "+\tUser() UserStore", " type IStore interface {"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestWriteFile_CreatesDirectories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a", "b", "c.go")
+	if err := WriteFile(path, []byte("package c\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "package c\n" {
+		t.Errorf("Unexpected content %q, err: %v", content, err)
+	}
+}
+
+func TestRemoveFile_DryRun(t *testing.T) {
+	setupDryRun(t)
+
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "user.go")
+	if err := os.WriteFile(existing, []byte("package pkg\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if err := RemoveFile(existing); err != nil {
+		t.Fatalf("RemoveFile failed: %v", err)
+	}
+	if err := RemoveFile(filepath.Join(tmpDir, "missing.go")); err != nil {
+		t.Fatalf("RemoveFile failed: %v", err)
+	}
+
+	if !Exists(existing) {
+		t.Error("File should not be removed in dry-run mode")
+	}
+	if _, err := ReadFile(existing); !os.IsNotExist(err) {
+		t.Errorf("ReadFile should return not exist for pending removal, got %v", err)
+	}
+
+	if Changes()[0].Action() != "delete" || Changes()[1].Action() != "unchanged" {
+		t.Errorf("Unexpected actions: %s, %s", Changes()[0].Action(), Changes()[1].Action())
+	}
+
+	var buf bytes.Buffer
+	if err := PrintChanges(&buf); err != nil {
+		t.Fatalf("PrintChanges failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "+++ /dev/null") || !strings.Contains(buf.String(), "-package pkg") {
+		t.Errorf("Expected removal diff, got:\n%s", buf.String())
+	}
+}
@@ -1,7 +1,8 @@
 package util
 
 import (
-	"fmt"
+	"bytes"
+	"go/format"
 	"io"
 	"os"
 	"path/filepath"
@@ -14,49 +15,56 @@ import (
 
 var Overwrite bool
 
-// GenerateCode generate go source file.
-func GenerateCode(filePath, codeTemplate, name string, o any) error {
-	if Exists(filePath) && !Overwrite {
-		prompt := promptui.Prompt{
-			Label:     "Overwrite " + ansi.Color(filePath, "yellow"),
-			IsConfirm: true,
-		}
-
-		_, err := prompt.Run()
-		if err != nil {
-			return err
-		}
+// ConfirmOverwrite asks the user to confirm overwriting filePath, it returns an error if refused.
+func ConfirmOverwrite(filePath string) error {
+	prompt := promptui.Prompt{
+		Label:     "Overwrite " + ansi.Color(filePath, "yellow"),
+		IsConfirm: true,
 	}
 
-	directory := GetDirectoryFromPath(filePath)
-	err := os.MkdirAll(directory, 0755)
-	if err != nil {
-		return err
+	_, err := prompt.Run()
+
+	return err
+}
+
+// ConfirmDelete asks the user to confirm deleting filePath, it returns an error if refused.
+func ConfirmDelete(filePath string) error {
+	prompt := promptui.Prompt{
+		Label:     "Delete " + ansi.Color(filePath, "yellow"),
+		IsConfirm: true,
 	}
 
+	_, err := prompt.Run()
+
+	return err
+}
+
+// RenderCode executes codeTemplate with o and returns the content of filePath.
+func RenderCode(filePath, codeTemplate, name string, o any) ([]byte, error) {
 	tmpl := template.New(name)
 	if name == "init" {
 		tmpl.Delims("{[", "]}")
 	}
-	tmpl, err = tmpl.Parse(codeTemplate)
+	tmpl, err := tmpl.Parse(codeTemplate)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fd, err := os.Create(filePath)
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, o)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer fd.Close()
 
-	err = tmpl.Execute(fd, o)
-	if err != nil {
-		return err
+	// Format go code, keep it as is if it doesn't parse.
+	content := buf.Bytes()
+	if filepath.Ext(filePath) == ".go" {
+		if formatted, err := format.Source(content); err == nil {
+			content = formatted
+		}
 	}
 
-	fmt.Printf("%s %s\n", ansi.Color("Generated:", "green"), filePath)
-
-	return nil
+	return content, nil
 }
 
 func Exists(fileToCheck string) bool {